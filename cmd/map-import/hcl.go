@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// mapConfiguration returns a haproxy_map resource holding entries and the
+// import block of the whole map.
+func mapConfiguration(mapName string, resourceName string, entries map[string]string) string {
+	var hcl strings.Builder
+	fmt.Fprintf(&hcl, "import {\n  to = haproxy_map.%s\n  id = %s\n}\n\n", resourceName, hclString("map/"+mapName))
+	fmt.Fprintf(&hcl, "resource \"haproxy_map\" %s {\n  name = %s\n", hclString(resourceName), hclString(mapName))
+	fmt.Fprintf(&hcl, "  entries = %s\n}\n", hclMap(entries, "  "))
+	return hcl.String()
+}
+
+// mapsConfiguration returns a haproxy_maps resource with one instance per
+// entry and the import block of every entry.
+func mapsConfiguration(mapName string, resourceName string, entries map[string]string) string {
+	var hcl strings.Builder
+	for _, key := range sortedKeys(entries) {
+		fmt.Fprintf(&hcl, "import {\n  to = haproxy_maps.%s[%s]\n  id = %s\n}\n\n", resourceName, hclString(key), hclString("map/"+mapName+"/entrie/"+key))
+	}
+	fmt.Fprintf(&hcl, "locals {\n  %s_entries = %s\n}\n\n", resourceName, hclMap(entries, "  "))
+	fmt.Fprintf(&hcl, "resource \"haproxy_maps\" %s {\n  for_each = local.%s_entries\n\n", hclString(resourceName), resourceName)
+	fmt.Fprintf(&hcl, "  map   = %s\n  key   = each.key\n  value = each.value\n}\n", hclString(mapName))
+	return hcl.String()
+}
+
+func hclMap(entries map[string]string, indent string) string {
+	if len(entries) == 0 {
+		return "{}"
+	}
+
+	var hcl strings.Builder
+	hcl.WriteString("{\n")
+	for _, key := range sortedKeys(entries) {
+		fmt.Fprintf(&hcl, "%s  %s = %s\n", indent, hclString(key), hclString(entries[key]))
+	}
+	hcl.WriteString(indent + "}")
+	return hcl.String()
+}
+
+// hclString quotes s as an HCL string literal, escaping the template
+// sequences so the value is taken literally.
+func hclString(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+var invalidIdentifierCharacters = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// resourceIdentifier turns a map name into a valid resource name.
+func resourceIdentifier(mapName string) string {
+	name := invalidIdentifierCharacters.ReplaceAllString(mapName, "_")
+	if name == "" || !regexp.MustCompile(`^[A-Za-z_]`).MatchString(name) {
+		name = "map_" + name
+	}
+	return name
+}
+
+func sortedKeys(entries map[string]string) []string {
+	keys := []string{}
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
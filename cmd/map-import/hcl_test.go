@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+var testEntries = map[string]string{
+	"/metrics":                    "50",
+	"https://example.com|api?q=1": "enable",
+	`quote"and\backslash`:         "${not_interpolated} %{not_a_directive}",
+	"日本":                          "unicode",
+}
+
+func TestMapConfiguration(t *testing.T) {
+	expected := `import {
+  to = haproxy_map.ratelimit
+  id = "map/ratelimit"
+}
+
+resource "haproxy_map" "ratelimit" {
+  name = "ratelimit"
+  entries = {
+    "/metrics" = "50"
+    "https://example.com|api?q=1" = "enable"
+    "quote\"and\\backslash" = "$${not_interpolated} %%{not_a_directive}"
+    "日本" = "unicode"
+  }
+}
+`
+	configuration := mapConfiguration("ratelimit", "ratelimit", testEntries)
+	if configuration != expected {
+		t.Fatalf("unexpected configuration:\n%s", configuration)
+	}
+	assertValidHCL(t, configuration)
+}
+
+func TestMapsConfiguration(t *testing.T) {
+	configuration := mapsConfiguration("ratelimit", "ratelimit", testEntries)
+	assertValidHCL(t, configuration)
+
+	file, _ := hclsyntax.ParseConfig([]byte(configuration), "generated.tf", hcl.Pos{Line: 1, Column: 1})
+	imports := 0
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "import" {
+			continue
+		}
+		imports++
+		id, diags := block.Body.Attributes["id"].Expr.Value(nil)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		if id.AsString() == "map/ratelimit/entrie/"+`quote"and\backslash` {
+			return
+		}
+	}
+	t.Fatalf("expected the import id of every entry to be kept as is, got %d import blocks", imports)
+}
+
+func TestResourceIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"ratelimit":                  "ratelimit",
+		"rate-limit.map":             "rate-limit_map",
+		"/etc/haproxy/maps/test.map": "_etc_haproxy_maps_test_map",
+		"2fa":                        "map_2fa",
+	}
+	for mapName, expected := range tests {
+		if name := resourceIdentifier(mapName); name != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, mapName, name)
+		}
+	}
+}
+
+func assertValidHCL(t *testing.T, content string) {
+	t.Helper()
+	if _, diags := hclsyntax.ParseConfig([]byte(content), "generated.tf", hcl.Pos{Line: 1, Column: 1}); diags.HasErrors() {
+		t.Fatalf("invalid HCL: %s\n%s", diags, content)
+	}
+}
@@ -0,0 +1,79 @@
+// Command map-import prints the HCL adopting an existing HAProxy map into
+// Terraform in one apply: the resources declaring its current entries and the
+// matching import blocks, which require Terraform 1.5 or later.
+//
+// The Dataplane API is reached with the provider environment variables
+// HAPROXY_SERVER, HAPROXY_USERNAME, HAPROXY_PASSWORD, HAPROXY_INSECURE and
+// HAPROXY_API_VERSION.
+//
+//	go run ./cmd/map-import -map ratelimit > ratelimit.tf
+//	go run ./cmd/map-import -map ratelimit -resource haproxy_maps > ratelimit.tf
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+func main() {
+	mapName := flag.String("map", "", "Name of the map to import")
+	resourceType := flag.String("resource", "haproxy_map", "Resource type declaring the entries: 'haproxy_map' for a single resource holding every entry, 'haproxy_maps' for one resource instance per entry")
+	resourceName := flag.String("name", "", "Name of the resource in the generated configuration. Defaults to the map name")
+	flag.Parse()
+
+	if *mapName == "" {
+		fmt.Fprintln(os.Stderr, "-map is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *resourceName == "" {
+		*resourceName = resourceIdentifier(*mapName)
+	}
+
+	if err := run(*mapName, *resourceType, *resourceName); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(mapName string, resourceType string, resourceName string) error {
+	insecure, _ := strconv.ParseBool(os.Getenv("HAPROXY_INSECURE"))
+	apiVersion := os.Getenv("HAPROXY_API_VERSION")
+
+	client, err := haproxy.NewClient(os.Getenv("HAPROXY_USERNAME"), os.Getenv("HAPROXY_PASSWORD"), os.Getenv("HAPROXY_SERVER"), insecure, apiVersion)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if apiVersion == "" || apiVersion == haproxy.APIVersionAuto {
+		if _, err := client.DetectAPIVersion(ctx); err != nil {
+			return err
+		}
+	}
+
+	mapEntries, err := client.GetMapEntries(ctx, mapName)
+	if err != nil {
+		return err
+	}
+
+	entries := map[string]string{}
+	for _, entrie := range mapEntries {
+		entries[entrie.Key] = entrie.Value
+	}
+
+	switch resourceType {
+	case "haproxy_map":
+		_, err = os.Stdout.WriteString(mapConfiguration(mapName, resourceName, entries))
+	case "haproxy_maps":
+		_, err = os.Stdout.WriteString(mapsConfiguration(mapName, resourceName, entries))
+	default:
+		err = fmt.Errorf("unsupported resource %s, expected haproxy_map or haproxy_maps", resourceType)
+	}
+	return err
+}
@@ -0,0 +1,10 @@
+# routing map owned by another team
+data "haproxy_map" "routing" {
+  name       = "routing"
+  key_prefix = "api."
+}
+
+resource "haproxy_backend" "api" {
+  for_each = toset(values(data.haproxy_map.routing.entries))
+  name     = each.value
+}
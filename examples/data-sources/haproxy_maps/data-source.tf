@@ -0,0 +1,5 @@
+data "haproxy_maps" "all" {}
+
+output "map_files" {
+  value = { for m in data.haproxy_maps.all.maps : m.name => m.file }
+}
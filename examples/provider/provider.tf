@@ -3,7 +3,39 @@ provider "haproxy" {
   username    = "admin"          # optionally use HAPROXY_USERNAME env var
   password    = "adminpwd"       # optionally use HAPROXY_PASSWORD env var
 
-  # you may need to allow insecure TLS communications unless you have configured
-  # certificates for your server
+  # use plain http, e.g. for a Dataplane API only listening on localhost
   insecure = true # optionally use HAPROXY_INSECURE env var
 }
+
+# Dataplane API of a sidecar, listening on a Unix socket
+provider "haproxy" {
+  alias       = "sidecar"
+  server_addr = "unix:///var/run/dataplaneapi.sock"
+  api_version = "v3" # detected when not set, optionally use HAPROXY_API_VERSION env var
+  username    = "admin"
+  password    = "adminpwd"
+  insecure    = true
+}
+
+# Dataplane API behind an internal PKI, with client certificate authentication
+provider "haproxy" {
+  alias       = "mtls"
+  server_addr = "https://lb.internal/dataplane" # full URL with a base path
+  username    = "admin"
+  password    = "adminpwd"
+  insecure    = false
+
+  ca_file     = "/etc/pki/internal-ca.pem"    # optionally use HAPROXY_CA_FILE env var
+  client_cert = "/etc/pki/terraform.crt"      # optionally use HAPROXY_CLIENT_CERT env var
+  client_key  = file("/etc/pki/terraform.key") # file path or inline PEM, optionally use HAPROXY_CLIENT_KEY env var
+}
+
+# Pair of HAProxy nodes, every change is applied to both
+provider "haproxy" {
+  alias           = "pair"
+  endpoints       = ["lb1.internal:5555", "lb2.internal:5555"]
+  partial_failure = "rollback" # "fail" by default, or "continue"
+  username        = "admin"
+  password        = "adminpwd"
+  insecure        = true
+}
@@ -0,0 +1,3 @@
+# import from provider configured site, by acl name or by index
+terraform import haproxy_acl.is_test_ok frontend/test_map/acl/is_test_ok
+terraform import haproxy_acl.is_test_ok frontend/test_map/acl/0
\ No newline at end of file
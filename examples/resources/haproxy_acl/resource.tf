@@ -0,0 +1,7 @@
+resource "haproxy_acl" "is_test_ok" {
+  parent_type = "frontend"
+  parent_name = "test_map"
+  acl_name    = "is_test_ok"
+  criterion   = "src,map_str(/etc/haproxy/maps/test.map)"
+  value       = "-m found"
+}
@@ -0,0 +1,2 @@
+# import every entry of the ACL file
+terraform import haproxy_acl_file.blocklist acl_file/blocklist
\ No newline at end of file
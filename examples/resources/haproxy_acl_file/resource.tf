@@ -0,0 +1,10 @@
+# loaded with: http-request deny if { src -f /etc/haproxy/general/blocklist.acl }
+resource "haproxy_acl_file" "blocklist" {
+  acl_file = "blocklist"
+  values   = ["203.0.113.7", "198.51.100.0/24"]
+
+  # delete the entries of the file not declared above
+  remove_unmanaged = true
+  # keep the entries across reloads
+  storage_name = "blocklist.acl"
+}
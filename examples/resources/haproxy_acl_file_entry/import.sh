@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_acl_file_entry.attacker acl_file/blocklist/entry/203.0.113.7
\ No newline at end of file
@@ -0,0 +1,5 @@
+resource "haproxy_acl_file_entry" "attacker" {
+  acl_file     = "blocklist"
+  value        = "203.0.113.7"
+  storage_name = "blocklist.acl"
+}
@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_backend.my-backend my-backend
\ No newline at end of file
@@ -0,0 +1,21 @@
+resource "haproxy_backend" "my-backend" {
+  name            = "my-backend"
+  mode            = "http"
+  connect_timeout = 5000
+  server_timeout  = 10000
+
+  balance {
+    algorithm = "roundrobin"
+  }
+
+  adv_check = "httpchk"
+  httpchk_params {
+    method = "GET"
+    uri    = "/healthz"
+  }
+
+  cookie {
+    name = "SERVERID"
+    type = "insert"
+  }
+}
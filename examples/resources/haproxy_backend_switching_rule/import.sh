@@ -0,0 +1,3 @@
+# import from provider configured site, by backend name or by index
+terraform import haproxy_backend_switching_rule.api frontend/my-frontend/backend_switching_rule/api
+terraform import haproxy_backend_switching_rule.by-host frontend/my-frontend/backend_switching_rule/1
\ No newline at end of file
@@ -0,0 +1,15 @@
+# Route on a static condition
+resource "haproxy_backend_switching_rule" "api" {
+  frontend  = "my-frontend"
+  backend   = "api"
+  cond      = "if"
+  cond_test = "{ path_beg /api }"
+}
+
+# Pick the backend from a map managed with haproxy_maps
+resource "haproxy_backend_switching_rule" "by-host" {
+  frontend  = "my-frontend"
+  backend   = "%[req.hdr(host),lower,map(/etc/haproxy/maps/hosts.map)]"
+  cond      = "if"
+  cond_test = "{ req.hdr(host),lower,map(/etc/haproxy/maps/hosts.map) -m found }"
+}
@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_bind.https frontend/my-frontend/bind/https
\ No newline at end of file
@@ -0,0 +1,10 @@
+resource "haproxy_bind" "https" {
+  parent_name     = "my-frontend"
+  parent_type     = "frontend"
+  name            = "https"
+  address         = "*"
+  port            = 443
+  ssl             = true
+  ssl_certificate = "/etc/haproxy/ssl/site.pem"
+  alpn            = "h2,http/1.1"
+}
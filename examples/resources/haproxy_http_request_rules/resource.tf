@@ -0,0 +1,24 @@
+resource "haproxy_http_request_rules" "my-frontend" {
+  parent_type = "frontend"
+  parent_name = "my-frontend"
+
+  rule {
+    type       = "set-header"
+    hdr_name   = "X-Forwarded-Proto"
+    hdr_format = "https"
+  }
+
+  rule {
+    type        = "deny"
+    deny_status = 429
+    cond        = "if"
+    cond_test   = "{ path,map_beg(/etc/haproxy/maps/ratelimit.map) -m found }"
+  }
+
+  rule {
+    type         = "use-service"
+    service_name = "prometheus-exporter"
+    cond         = "if"
+    cond_test    = "{ path /metrics }"
+  }
+}
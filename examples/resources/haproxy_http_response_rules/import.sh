@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_http_response_rules.my-frontend frontend/my-frontend
\ No newline at end of file
@@ -0,0 +1,15 @@
+resource "haproxy_http_response_rules" "my-frontend" {
+  parent_type = "frontend"
+  parent_name = "my-frontend"
+
+  rule {
+    type     = "del-header"
+    hdr_name = "Server"
+  }
+
+  rule {
+    type       = "set-header"
+    hdr_name   = "Strict-Transport-Security"
+    hdr_format = "max-age=31536000"
+  }
+}
@@ -0,0 +1,2 @@
+# import every entry of the map
+terraform import haproxy_map.ratelimit map/ratelimit
\ No newline at end of file
@@ -0,0 +1,17 @@
+resource "haproxy_map" "ratelimit" {
+  name = "ratelimit"
+  entries = {
+    "/metrics" = "50"
+    "/api"     = "200"
+  }
+
+  # delete the entries of the map not declared above
+  remove_unmanaged = true
+}
+
+# large map, new entries are added 1000 at a time
+resource "haproxy_map" "blocklist" {
+  name      = "blocklist"
+  entries   = { for ip in var.blocked_ips : ip => "1" }
+  bulk_load = true
+}
@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_map_file.ratelimit ratelimit.map
\ No newline at end of file
@@ -0,0 +1,25 @@
+resource "haproxy_map_file" "ratelimit" {
+  name = "ratelimit.map"
+  entries = {
+    "/metrics" = "50"
+    "/api"     = "200"
+  }
+}
+
+resource "haproxy_map_file" "hosts" {
+  name    = "hosts.map"
+  content = file("${path.module}/hosts.map")
+}
+
+# the path of the file is used in expressions
+resource "haproxy_http_request_rules" "http" {
+  parent_type = "frontend"
+  parent_name = "http"
+
+  rule {
+    type        = "deny"
+    deny_status = 429
+    cond        = "if"
+    cond_test   = "{ path,map_beg(${haproxy_map_file.ratelimit.path}) -m found }"
+  }
+}
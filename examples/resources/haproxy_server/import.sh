@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_server.web1 backend/my-backend/server/web1
\ No newline at end of file
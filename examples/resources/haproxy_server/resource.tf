@@ -0,0 +1,11 @@
+resource "haproxy_server" "web1" {
+  backend = "my-backend"
+  name    = "web1"
+  address = "10.0.0.10"
+  port    = 8080
+  weight  = 100
+  check   = "enabled"
+  inter   = 2000
+  rise    = 2
+  fall    = 3
+}
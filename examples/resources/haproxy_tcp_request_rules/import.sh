@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_tcp_request_rules.my-tcp-frontend frontend/my-tcp-frontend
\ No newline at end of file
@@ -0,0 +1,35 @@
+resource "haproxy_tcp_request_rules" "my-tcp-frontend" {
+  parent_type = "frontend"
+  parent_name = "my-tcp-frontend"
+
+  rule {
+    type   = "connection"
+    action = "expect-proxy"
+  }
+
+  rule {
+    type        = "connection"
+    action      = "track-sc0"
+    track_key   = "src"
+    track_table = "per_ip_rates"
+  }
+
+  rule {
+    type      = "connection"
+    action    = "reject"
+    cond      = "if"
+    cond_test = "{ sc_conn_rate(0) gt 100 }"
+  }
+
+  rule {
+    type    = "inspect-delay"
+    timeout = 5000
+  }
+
+  rule {
+    type      = "content"
+    action    = "accept"
+    cond      = "if"
+    cond_test = "{ req.ssl_hello_type 1 }"
+  }
+}
@@ -0,0 +1,2 @@
+# import from provider configured site
+terraform import haproxy_tcp_response_rules.my-tcp-backend backend/my-tcp-backend
\ No newline at end of file
@@ -0,0 +1,16 @@
+resource "haproxy_tcp_response_rules" "my-tcp-backend" {
+  parent_type = "backend"
+  parent_name = "my-tcp-backend"
+
+  rule {
+    type    = "inspect-delay"
+    timeout = 1000
+  }
+
+  rule {
+    type      = "content"
+    action    = "accept"
+    cond      = "if"
+    cond_test = "{ res.len gt 0 }"
+  }
+}
@@ -0,0 +1,77 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// GetAcls returns the ACLs of a frontend or backend in configuration order.
+// When transactionId is not empty, the list reflects the pending transaction.
+func (c *Client) GetAcls(ctx context.Context, transactionId string, parentType string, parentName string) ([]models.Acl, error) {
+	url := c.sectionURL(parentType, parentName, "acls", "", transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.Acl{}
+	if err := c.sendConfigurationRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) CreateAcl(ctx context.Context, transactionId string, parentType string, parentName string, acl models.Acl) (*models.Acl, error) {
+	url := c.sectionURL(parentType, parentName, "acls", "", transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(acl)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Acl{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) UpdateAcl(ctx context.Context, transactionId string, parentType string, parentName string, acl models.Acl) (*models.Acl, error) {
+	url := c.sectionIndexURL(parentType, parentName, "acls", *acl.Index, transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(acl)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Acl{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) DeleteAcl(ctx context.Context, transactionId string, parentType string, parentName string, index int) error {
+	url := c.sectionIndexURL(parentType, parentName, "acls", index, transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendRequest(req, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// GetAclFiles returns the ACL files loaded by HAProxy.
+func (c *Client) GetAclFiles(ctx context.Context) ([]models.AclFile, error) {
+	url := c.base_url + "/services/haproxy/runtime/acls"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.AclFile{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAclFile returns the ACL file loaded by HAProxy named name, which is
+// either its runtime ID, its path, its file name or its file name without
+// extension. It returns ErrNotFound if there is none.
+func (c *Client) GetAclFile(ctx context.Context, name string) (*models.AclFile, error) {
+	aclFiles, err := c.GetAclFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, aclFile := range aclFiles {
+		base := path.Base(aclFile.StorageName)
+		if name == aclFile.Id || name == aclFile.StorageName || name == base || name == strings.TrimSuffix(base, path.Ext(base)) {
+			return &aclFile, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (c *Client) GetAclFileEntries(ctx context.Context, aclId string) ([]models.AclFileEntry, error) {
+	url := c.base_url + "/services/haproxy/runtime/acls/" + escapePathSegment(aclId) + "/entries"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.AclFileEntry{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAclFileEntry returns the entry holding value of the ACL file aclId, or
+// ErrNotFound. Entries are addressed by a runtime ID, not by their value.
+func (c *Client) GetAclFileEntry(ctx context.Context, aclId string, value string) (*models.AclFileEntry, error) {
+	entries, err := c.GetAclFileEntries(ctx, aclId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Value == value {
+			return &entry, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (c *Client) CreateAclFileEntry(ctx context.Context, aclId string, value string) (*models.AclFileEntry, error) {
+	url := c.base_url + "/services/haproxy/runtime/acls/" + escapePathSegment(aclId) + "/entries"
+	bodyStr, _ := json.Marshal(models.AclFileEntry{Value: value})
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.AclFileEntry{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) DeleteAclFileEntry(ctx context.Context, aclId string, entryId string) error {
+	url := c.base_url + "/services/haproxy/runtime/acls/" + escapePathSegment(aclId) + "/entries/" + escapePathSegment(entryId)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.sendRequest(req, nil)
+}
+
+// AclSyncOptions tunes how SyncAclFileEntries changes a runtime ACL file.
+type AclSyncOptions struct {
+	// Stale are values deleted when not in values, typically the values
+	// managed previously.
+	Stale []string
+	// RemoveUnmanaged deletes every value not in values.
+	RemoveUnmanaged bool
+}
+
+// SyncAclFileEntries makes the runtime ACL file aclName hold values, adding
+// the missing ones and deleting the ones options ask for.
+func (c *Client) SyncAclFileEntries(ctx context.Context, aclName string, values []string, options AclSyncOptions) error {
+	aclFile, err := c.GetAclFile(ctx, aclName)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.GetAclFileEntries(ctx, aclFile.Id)
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, value := range values {
+		wanted[value] = true
+	}
+	stale := map[string]bool{}
+	for _, value := range options.Stale {
+		stale[value] = true
+	}
+
+	present := map[string]bool{}
+	for _, entry := range current {
+		present[entry.Value] = true
+		if wanted[entry.Value] || (!options.RemoveUnmanaged && !stale[entry.Value]) {
+			continue
+		}
+		if err := c.DeleteAclFileEntry(ctx, aclFile.Id, entry.Id); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+
+	toCreate := []string{}
+	for value := range wanted {
+		if !present[value] {
+			toCreate = append(toCreate, value)
+		}
+	}
+	sort.Strings(toCreate)
+
+	for _, value := range toCreate {
+		if _, err := c.CreateAclFileEntry(ctx, aclFile.Id, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PersistAclFile writes the runtime entries of the ACL file aclName to the
+// general storage file storageName, so they survive a reload of HAProxy.
+// Runtime ACL changes are otherwise lost on reload, there is no force_sync
+// for ACLs. storageName must be the file HAProxy loads the ACL file from.
+//
+// The whole file is rebuilt from the runtime entries on each write, so every
+// resource of an ACL file must persist it to the same storage file, see
+// ClaimAclStorage. Writes are only serialized within this client, another
+// process changing the same ACL file concurrently may be overwritten.
+func (c *Client) PersistAclFile(ctx context.Context, aclName string, storageName string) error {
+	c.persistMu.Lock()
+	defer c.persistMu.Unlock()
+
+	aclFile, err := c.GetAclFile(ctx, aclName)
+	if err != nil {
+		return err
+	}
+
+	entries, err := c.GetAclFileEntries(ctx, aclFile.Id)
+	if err != nil {
+		return err
+	}
+
+	var content strings.Builder
+	for _, entry := range entries {
+		content.WriteString(entry.Value + "\n")
+	}
+
+	err = c.uploadGeneralFile(ctx, "PUT", "/"+escapePathSegment(storageName), storageName, content.String())
+	if errors.Is(err, ErrNotFound) {
+		err = c.uploadGeneralFile(ctx, "POST", "", storageName, content.String())
+	}
+	return err
+}
+
+// ClaimAclStorage records that the ACL file aclName is persisted to the
+// storage file storageName, empty for none, by a resource. It fails if
+// another resource of the same ACL file claimed a different one, as the last
+// one written would otherwise win.
+func (c *Client) ClaimAclStorage(ctx context.Context, aclName string, storageName string) error {
+	// the ACL file may be named in several ways, its runtime ID identifies it
+	key := aclName
+	aclFile, err := c.GetAclFile(ctx, aclName)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err == nil {
+		key = aclFile.Id
+	}
+
+	c.aclStorageMu.Lock()
+	defer c.aclStorageMu.Unlock()
+
+	if c.aclStorage == nil {
+		c.aclStorage = map[string]string{}
+	}
+	claimed, ok := c.aclStorage[key]
+	if ok && claimed != storageName {
+		return fmt.Errorf("storage_name %q of ACL file %s differs from %q set by another resource of the same ACL file, they must all persist it to the same storage file", storageName, aclName, claimed)
+	}
+	c.aclStorage[key] = storageName
+	return nil
+}
+
+// uploadGeneralFile creates, or replaces, a file of the general storage.
+func (c *Client) uploadGeneralFile(ctx context.Context, method string, suffix string, name string, content string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file_upload", name)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write([]byte(content)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	url := c.base_url + "/services/haproxy/storage/general" + suffix
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.sendRequest(req, nil)
+}
@@ -0,0 +1,160 @@
+package haproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// fakeRuntimeAclAPI serves one runtime ACL file with ID 3 through the v2
+// layout, and the general storage.
+type fakeRuntimeAclAPI struct {
+	values  map[string]string
+	nextId  int
+	storage map[string]string
+}
+
+func (f *fakeRuntimeAclAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/v2/services/haproxy/runtime/acls":
+		w.Write([]byte(`[{"id": "3", "storage_name": "/etc/haproxy/general/blocklist.acl"}]`))
+	case r.Method == "GET" && r.URL.Path == "/v2/services/haproxy/runtime/acls/3/entries":
+		entries := []models.AclFileEntry{}
+		for id, value := range f.values {
+			entries = append(entries, models.AclFileEntry{Id: id, Value: value})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Value < entries[j].Value })
+		json.NewEncoder(w).Encode(entries)
+	case r.Method == "POST" && r.URL.Path == "/v2/services/haproxy/runtime/acls/3/entries":
+		entry := models.AclFileEntry{}
+		json.NewDecoder(r.Body).Decode(&entry)
+		f.nextId++
+		entry.Id = fmt.Sprintf("0x%d", f.nextId)
+		f.values[entry.Id] = entry.Value
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(entry)
+	case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/v2/services/haproxy/runtime/acls/3/entries/"):
+		delete(f.values, strings.TrimPrefix(r.URL.Path, "/v2/services/haproxy/runtime/acls/3/entries/"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/v2/services/haproxy/storage/general/blocklist.acl" && r.Method == "PUT",
+		r.URL.Path == "/v2/services/haproxy/storage/general" && r.Method == "POST":
+		file, header, err := r.FormFile("file_upload")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := f.storage[header.Filename]; !ok && r.Method == "PUT" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		f.storage[header.Filename] = string(content)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeRuntimeAclAPI) sortedValues() []string {
+	values := []string{}
+	for _, value := range f.values {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func TestGetAclFileByName(t *testing.T) {
+	client := newTestClient(t, &fakeRuntimeAclAPI{})
+
+	for _, name := range []string{"3", "/etc/haproxy/general/blocklist.acl", "blocklist.acl", "blocklist"} {
+		aclFile, err := client.GetAclFile(context.Background(), name)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", name, err)
+		}
+		if aclFile.Id != "3" {
+			t.Fatalf("unexpected ACL file %s for %s", aclFile.Id, name)
+		}
+	}
+
+	if _, err := client.GetAclFile(context.Background(), "allowlist"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSyncAclFileEntries(t *testing.T) {
+	tests := []struct {
+		name            string
+		stale           []string
+		removeUnmanaged bool
+		expected        []string
+	}{
+		{name: "keeps unmanaged entries", stale: []string{"10.0.0.1"}, expected: []string{"10.0.0.2", "10.0.0.3", "192.168.0.1"}},
+		{name: "removes unmanaged entries", removeUnmanaged: true, expected: []string{"10.0.0.2", "10.0.0.3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &fakeRuntimeAclAPI{values: map[string]string{"0x1": "10.0.0.1", "0x2": "10.0.0.2", "0x3": "192.168.0.1"}, nextId: 3}
+			client := newTestClient(t, api)
+
+			err := client.SyncAclFileEntries(context.Background(), "blocklist", []string{"10.0.0.2", "10.0.0.3"}, AclSyncOptions{
+				Stale:           test.stale,
+				RemoveUnmanaged: test.removeUnmanaged,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if values := api.sortedValues(); !reflect.DeepEqual(values, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestPersistAclFile(t *testing.T) {
+	api := &fakeRuntimeAclAPI{values: map[string]string{"0x1": "10.0.0.1", "0x2": "10.0.0.2"}, storage: map[string]string{}}
+	client := newTestClient(t, api)
+
+	for i := 0; i < 2; i++ {
+		if err := client.PersistAclFile(context.Background(), "blocklist", "blocklist.acl"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if content := api.storage["blocklist.acl"]; content != "10.0.0.1\n10.0.0.2\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+}
+
+func TestClaimAclStorageRejectsMismatches(t *testing.T) {
+	client := newTestClient(t, &fakeRuntimeAclAPI{})
+	ctx := context.Background()
+
+	if err := client.ClaimAclStorage(ctx, "blocklist", "blocklist.acl"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the same ACL file named by its path
+	if err := client.ClaimAclStorage(ctx, "/etc/haproxy/general/blocklist.acl", "blocklist.acl"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := client.ClaimAclStorage(ctx, "3", "other.acl"); err == nil {
+		t.Fatal("expected a different storage file of the same ACL file to be rejected")
+	}
+	if err := client.ClaimAclStorage(ctx, "blocklist", ""); err == nil {
+		t.Fatal("expected a resource not persisting the ACL file to be rejected")
+	}
+	if err := client.ClaimAclStorage(ctx, "allowlist", "allowlist.acl"); err != nil {
+		t.Fatalf("unexpected error for another ACL file: %s", err)
+	}
+}
@@ -0,0 +1,75 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func (c *Client) GetBackend(ctx context.Context, backend models.Backend) (*models.Backend, error) {
+	url := c.base_url + "/services/haproxy/configuration/backends/" + escapePathSegment(backend.Name)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.Backend{}
+	if err := c.sendConfigurationRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) CreateBackend(ctx context.Context, transactionId string, backend models.Backend) (*models.Backend, error) {
+	url := c.base_url + "/services/haproxy/configuration/backends" + encodeQuery(transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(backend)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Backend{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) UpdateBackend(ctx context.Context, transactionId string, backend models.Backend) (*models.Backend, error) {
+	url := c.base_url + "/services/haproxy/configuration/backends/" + escapePathSegment(backend.Name) + encodeQuery(transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(backend)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Backend{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) DeleteBackend(ctx context.Context, transactionId string, backend models.Backend) error {
+	url := c.base_url + "/services/haproxy/configuration/backends/" + escapePathSegment(backend.Name) + encodeQuery(transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendRequest(req, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
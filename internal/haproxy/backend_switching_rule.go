@@ -0,0 +1,78 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// GetBackendSwitchingRules returns the use_backend rules of a frontend in
+// configuration order. When transactionId is not empty, the list reflects the
+// pending transaction.
+func (c *Client) GetBackendSwitchingRules(ctx context.Context, transactionId string, frontendName string) ([]models.BackendSwitchingRule, error) {
+	url := c.sectionURL("frontend", frontendName, "backend_switching_rules", "", transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.BackendSwitchingRule{}
+	if err := c.sendConfigurationRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) CreateBackendSwitchingRule(ctx context.Context, transactionId string, frontendName string, rule models.BackendSwitchingRule) (*models.BackendSwitchingRule, error) {
+	url := c.sectionURL("frontend", frontendName, "backend_switching_rules", "", transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(rule)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.BackendSwitchingRule{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) UpdateBackendSwitchingRule(ctx context.Context, transactionId string, frontendName string, rule models.BackendSwitchingRule) (*models.BackendSwitchingRule, error) {
+	url := c.sectionIndexURL("frontend", frontendName, "backend_switching_rules", *rule.Index, transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(rule)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.BackendSwitchingRule{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) DeleteBackendSwitchingRule(ctx context.Context, transactionId string, frontendName string, index int) error {
+	url := c.sectionIndexURL("frontend", frontendName, "backend_switching_rules", index, transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendRequest(req, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package haproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBatchAborted is returned to the changes of a transaction batch that was
+// rolled back because another change of the same batch failed.
+var ErrBatchAborted = errors.New("transaction rolled back because another change in the same batch failed")
+
+// transactionBatch is a Data Plane API transaction shared by every change
+// made while it is open. It is committed once, when its last participant is
+// done and no other change joined it during the batch window.
+type transactionBatch struct {
+	id           string
+	participants int
+	failure      error
+	result       error
+	timer        *time.Timer
+	done         chan struct{}
+
+	// changes made inside a transaction are serialized, the Data Plane API
+	// doesn't guarantee concurrent writes to the same transaction are safe.
+	mu sync.Mutex
+}
+
+// WithTransaction runs fn inside the transaction batch currently open, or
+// opens one on the current configuration version. It returns once the batch
+// has been committed, or rolled back if any of its changes failed, so
+// concurrent changes of a single terraform apply end up in one transaction
+// and cause a single reload.
+func (c *Client) WithTransaction(ctx context.Context, fn func(transactionId string) error) error {
+	batch, err := c.joinBatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	batch.mu.Lock()
+	err = fn(batch.id)
+	batch.mu.Unlock()
+
+	c.leaveBatch(batch, err)
+	<-batch.done
+
+	if err != nil {
+		return err
+	}
+	return batch.result
+}
+
+func (c *Client) joinBatch(ctx context.Context) (*transactionBatch, error) {
+	c.batchMu.Lock()
+	if batch := c.joinOpenBatch(); batch != nil {
+		c.batchMu.Unlock()
+		return batch, nil
+	}
+	c.batchMu.Unlock()
+
+	// the transaction is opened without holding batchMu, so that changes
+	// leaving or closing other batches aren't blocked by the API calls
+	configuration, err := c.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := c.CreateTransaction(ctx, configuration.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	c.batchMu.Lock()
+	if batch := c.joinOpenBatch(); batch != nil {
+		c.batchMu.Unlock()
+
+		// another change opened a batch in the meantime, the transaction
+		// opened here is surplus. Failing to delete it doesn't affect the
+		// change, it is outdated by the batch commit and removed as stale.
+		_ = c.RollbackTransaction(ctx, transaction.Id)
+		return batch, nil
+	}
+	c.batch = &transactionBatch{
+		id:           transaction.Id,
+		participants: 1,
+		done:         make(chan struct{}),
+	}
+	batch := c.batch
+	c.batchMu.Unlock()
+	return batch, nil
+}
+
+// joinOpenBatch adds a participant to the batch currently open, if any. It
+// must be called with batchMu held.
+func (c *Client) joinOpenBatch() *transactionBatch {
+	batch := c.batch
+	if batch == nil {
+		return nil
+	}
+	if batch.timer != nil {
+		batch.timer.Stop()
+		batch.timer = nil
+	}
+	batch.participants++
+	return batch
+}
+
+func (c *Client) leaveBatch(batch *transactionBatch, err error) {
+	c.batchMu.Lock()
+	defer c.batchMu.Unlock()
+
+	if err != nil && batch.failure == nil {
+		batch.failure = err
+	}
+
+	batch.participants--
+	if batch.participants == 0 {
+		batch.timer = time.AfterFunc(c.TransactionBatchWindow, func() {
+			c.closeBatch(batch)
+		})
+	}
+}
+
+func (c *Client) closeBatch(batch *transactionBatch) {
+	c.batchMu.Lock()
+	if batch.participants > 0 {
+		// a change joined after the window expired, it will close the
+		// batch when it leaves
+		c.batchMu.Unlock()
+		return
+	}
+	if c.batch == batch {
+		c.batch = nil
+	}
+	c.batchMu.Unlock()
+
+	// the batch outlives the changes that opened and joined it, so it is
+	// committed or rolled back regardless of their cancellation
+	ctx := context.Background()
+
+	if batch.failure != nil {
+		batch.result = fmt.Errorf("%w: %s", ErrBatchAborted, batch.failure)
+	} else {
+		_, batch.result = c.CommitTransaction(ctx, batch.id)
+	}
+
+	// a failed transaction is never left behind, whether a change or the
+	// commit itself failed
+	if batch.result != nil {
+		if err := c.RollbackTransaction(ctx, batch.id); err != nil {
+			batch.result = fmt.Errorf("%w (rolling back transaction %s failed: %s)", batch.result, batch.id, err)
+		}
+	}
+
+	close(batch.done)
+}
@@ -0,0 +1,179 @@
+package haproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeTransactionAPI struct {
+	created   int32
+	committed int32
+	deleted   int32
+
+	failCommit bool
+
+	// creating, when set, holds every transaction creation until all the
+	// expected ones are in flight
+	creating *sync.WaitGroup
+}
+
+func (f *fakeTransactionAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/configuration/raw"):
+		w.Write([]byte(`{"_version": 1, "data": ""}`))
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/transactions"):
+		id := atomic.AddInt32(&f.created, 1)
+		if f.creating != nil {
+			f.creating.Done()
+			f.creating.Wait()
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"_version": 1, "id": "tx-%d", "status": "in_progress"}`, id)
+	case r.Method == "PUT" && strings.Contains(r.URL.Path, "/transactions/tx-"):
+		atomic.AddInt32(&f.committed, 1)
+		if f.failCommit {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"code": 409, "message": "version mismatch"}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"_version": 2, "status": "success"}`))
+	case r.Method == "DELETE" && strings.Contains(r.URL.Path, "/transactions/tx-"):
+		atomic.AddInt32(&f.deleted, 1)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("admin", "adminpwd", server.URL, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.TransactionBatchWindow = 50 * time.Millisecond
+	return client
+}
+
+func runConcurrently(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestWithTransactionCoalescesConcurrentChanges(t *testing.T) {
+	api := &fakeTransactionAPI{}
+	client := newTestClient(t, api)
+
+	ids := make([]string, 5)
+	errs := runConcurrently(5, func(i int) error {
+		return client.WithTransaction(context.Background(), func(transactionId string) error {
+			ids[i] = transactionId
+			return nil
+		})
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if ids[i] != ids[0] {
+			t.Fatalf("expected every change in one transaction, got %v", ids)
+		}
+	}
+	// changes racing to open the batch may each create a transaction, only
+	// one is committed and the others are deleted
+	if api.committed != 1 || api.deleted != api.created-1 {
+		t.Fatalf("expected 1 transaction committed once, got created=%d committed=%d deleted=%d", api.created, api.committed, api.deleted)
+	}
+}
+
+func TestWithTransactionDeletesSurplusTransactions(t *testing.T) {
+	creating := &sync.WaitGroup{}
+	creating.Add(2)
+	api := &fakeTransactionAPI{creating: creating}
+	client := newTestClient(t, api)
+
+	ids := make([]string, 2)
+	errs := runConcurrently(2, func(i int) error {
+		return client.WithTransaction(context.Background(), func(transactionId string) error {
+			ids[i] = transactionId
+			return nil
+		})
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if ids[0] != ids[1] {
+		t.Fatalf("expected both changes in the published transaction, got %v", ids)
+	}
+	if api.created != 2 || api.committed != 1 || api.deleted != 1 {
+		t.Fatalf("expected the surplus transaction to be deleted, got created=%d committed=%d deleted=%d", api.created, api.committed, api.deleted)
+	}
+}
+
+func TestWithTransactionRollsBackBatchOnFailure(t *testing.T) {
+	api := &fakeTransactionAPI{}
+	client := newTestClient(t, api)
+	failure := errors.New("invalid frontend")
+
+	errs := runConcurrently(3, func(i int) error {
+		return client.WithTransaction(context.Background(), func(transactionId string) error {
+			if i == 0 {
+				return failure
+			}
+			return nil
+		})
+	})
+
+	if !errors.Is(errs[0], failure) {
+		t.Fatalf("expected the failing change to get its own error, got %v", errs[0])
+	}
+	for _, err := range errs[1:] {
+		if !errors.Is(err, ErrBatchAborted) {
+			t.Fatalf("expected ErrBatchAborted, got %v", err)
+		}
+	}
+	if api.committed != 0 || api.deleted != api.created {
+		t.Fatalf("expected the transaction to be rolled back, got committed=%d deleted=%d", api.committed, api.deleted)
+	}
+}
+
+func TestWithTransactionRollsBackOnCommitFailure(t *testing.T) {
+	api := &fakeTransactionAPI{failCommit: true}
+	client := newTestClient(t, api)
+
+	err := client.WithTransaction(context.Background(), func(transactionId string) error {
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("expected the commit error")
+	}
+	if api.committed != 1 || api.deleted != 1 {
+		t.Fatalf("expected the transaction to be rolled back after the failed commit, got committed=%d deleted=%d", api.committed, api.deleted)
+	}
+}
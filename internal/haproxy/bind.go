@@ -0,0 +1,76 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func (c *Client) GetBind(ctx context.Context, parentType string, parentName string, bind models.Bind) (*models.Bind, error) {
+	url := c.sectionURL(parentType, parentName, "binds", bind.Name, url.Values{})
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.Bind{}
+	if err := c.sendConfigurationRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) CreateBind(ctx context.Context, transactionId string, parentType string, parentName string, bind models.Bind) (*models.Bind, error) {
+	url := c.sectionURL(parentType, parentName, "binds", "", transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(bind)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Bind{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) UpdateBind(ctx context.Context, transactionId string, parentType string, parentName string, bind models.Bind) (*models.Bind, error) {
+	url := c.sectionURL(parentType, parentName, "binds", bind.Name, transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(bind)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Bind{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) DeleteBind(ctx context.Context, transactionId string, parentType string, parentName string, bind models.Bind) error {
+	url := c.sectionURL(parentType, parentName, "binds", bind.Name, transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendRequest(req, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
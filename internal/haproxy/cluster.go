@@ -0,0 +1,616 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// Partial failure policies of a cluster, deciding what happens when a change
+// succeeds on some nodes and fails on others.
+const (
+	// PartialFailureFail stops at the first node failing. Nodes already
+	// changed keep the change.
+	PartialFailureFail = "fail"
+	// PartialFailureRollback stops at the first node failing and restores
+	// the configuration of the nodes already changed. Runtime changes made
+	// outside of a transaction, such as map entries, can't be rolled back.
+	PartialFailureRollback = "rollback"
+	// PartialFailureContinue applies the change to every node it can and
+	// only logs the nodes failing, which show up as drift on the next read.
+	PartialFailureContinue = "continue"
+)
+
+// NodeStatus is the outcome of an operation on one node of a cluster.
+type NodeStatus string
+
+const (
+	NodeApplied    NodeStatus = "applied"
+	NodePending    NodeStatus = "pending commit"
+	NodeFailed     NodeStatus = "failed"
+	NodeSkipped    NodeStatus = "skipped"
+	NodeRolledBack NodeStatus = "rolled back"
+)
+
+type NodeResult struct {
+	Endpoint string
+	Status   NodeStatus
+	Err      error
+}
+
+// ClusterError reports the outcome of an operation on every node of a
+// cluster when it failed on some of them.
+type ClusterError struct {
+	Operation string
+	Results   []NodeResult
+}
+
+func (e *ClusterError) Error() string {
+	failed := 0
+	lines := []string{}
+	for _, result := range e.Results {
+		line := fmt.Sprintf("  - %s: %s", result.Endpoint, result.Status)
+		if result.Err != nil {
+			failed++
+			line += ": " + result.Err.Error()
+		}
+		lines = append(lines, line)
+	}
+	return fmt.Sprintf("%s failed on %d of %d nodes:\n%s", e.Operation, failed, len(e.Results), strings.Join(lines, "\n"))
+}
+
+// Unwrap returns the error of the first failing node as long as the
+// operation was applied nowhere, so it is retried as it would be on a single
+// node. An operation applied to part of the cluster is never retried.
+func (e *ClusterError) Unwrap() error {
+	var first error
+	for _, result := range e.Results {
+		if result.Status == NodeApplied {
+			return nil
+		}
+		if first == nil && result.Err != nil {
+			first = result.Err
+		}
+	}
+	return first
+}
+
+// clusterTransaction is a transaction opened on every node of a cluster.
+type clusterTransaction struct {
+	// ids are the transaction of each node, empty for a node on which it
+	// couldn't be opened
+	ids []string
+	// versions are the configuration versions the transactions were opened
+	// on, committing one makes its node move to the next version
+	versions []int
+	// failures are the changes that failed on each node under the continue
+	// policy, the transaction of such a node is not committed
+	failures []error
+}
+
+// NewCluster returns a client applying every change to each of nodes, in
+// order, and reading from all of them. Every node gets its own transaction
+// opened on its own configuration version. Reads return the response of a
+// node differing from the first one, if any, so drift on any node shows up
+// as a diff. An object missing on some nodes reads as not found, the create
+// planned then updates it on the nodes already having it.
+func NewCluster(nodes []*Client, partialFailurePolicy string) *Client {
+	first := nodes[0]
+	return &Client{
+		HTTPClient:           first.HTTPClient,
+		endpoint:             first.endpoint,
+		base_url:             first.base_url,
+		apiVersion:           first.apiVersion,
+		RetryAttempts:        DefaultRetryAttempts,
+		RetryMaxWait:         DefaultRetryMaxWait,
+		nodes:                nodes,
+		PartialFailurePolicy: partialFailurePolicy,
+		clusterTransactions:  map[string]*clusterTransaction{},
+	}
+}
+
+func (c *Client) getClusterTransaction(transactionId string) (*clusterTransaction, bool) {
+	c.clusterMu.Lock()
+	defer c.clusterMu.Unlock()
+	tx, ok := c.clusterTransactions[transactionId]
+	return tx, ok
+}
+
+func (c *Client) dropClusterTransaction(transactionId string) (*clusterTransaction, bool) {
+	c.clusterMu.Lock()
+	defer c.clusterMu.Unlock()
+	tx, ok := c.clusterTransactions[transactionId]
+	delete(c.clusterTransactions, transactionId)
+	return tx, ok
+}
+
+// sendClusterRequest sends req to every node and decodes the response
+// standing for the cluster into v.
+func (c *Client) sendClusterRequest(req *http.Request, v interface{}) error {
+	var body []byte
+	if req.Body != nil {
+		content, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		body = content
+	}
+
+	var tx *clusterTransaction
+	if transactionId := req.URL.Query().Get("transaction_id"); transactionId != "" {
+		var ok bool
+		if tx, ok = c.getClusterTransaction(transactionId); !ok {
+			return fmt.Errorf("unknown transaction %s", transactionId)
+		}
+	}
+
+	path := strings.SplitN(strings.TrimPrefix(req.URL.String(), c.base_url), "?", 2)[0]
+	operation := req.Method + " " + path
+	read := req.Method == "GET"
+	continueOnFailure := c.PartialFailurePolicy == PartialFailureContinue
+
+	results := make([]NodeResult, len(c.nodes))
+	bodies := make([][]byte, len(c.nodes))
+	failed, stop := false, false
+	succeeded := func(i int) {
+		results[i].Status = NodeApplied
+		if tx != nil {
+			results[i].Status = NodePending
+		}
+	}
+	fail := func(i int, err error) {
+		results[i] = NodeResult{Endpoint: c.nodes[i].endpoint, Status: NodeFailed, Err: err}
+		failed = true
+		if !read && !continueOnFailure {
+			stop = true
+		}
+		if !read && continueOnFailure && tx != nil {
+			tx.failures[i] = err
+		}
+	}
+
+	// drifted are the nodes on which a change failed only because the object
+	// is missing, or already exists, there and not necessarily elsewhere
+	drifted := map[int]*http.Request{}
+	for i, node := range c.nodes {
+		results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeSkipped}
+		if stop || (tx != nil && (tx.ids[i] == "" || tx.failures[i] != nil)) {
+			continue
+		}
+
+		nodeReq, err := c.nodeRequest(req, i, tx, body)
+		if err == nil {
+			bodies[i], err = node.do(nodeReq)
+		}
+		if err != nil {
+			if !read && tx != nil && upsertable(nodeReq, err) {
+				results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeFailed, Err: err}
+				drifted[i] = nodeReq
+				continue
+			}
+			if read && continueOnFailure && !errors.Is(err, ErrNotFound) {
+				// an unreachable node doesn't hide the others, a missing
+				// object does as it is drift
+				log.Printf("[WARN] %s failed on %s, ignoring the node: %s", operation, node.endpoint, err)
+				results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeSkipped}
+				continue
+			}
+			fail(i, err)
+			continue
+		}
+
+		succeeded(i)
+	}
+
+	if len(drifted) > 0 {
+		c.upsertDriftedNodes(drifted, body, results, bodies, succeeded, fail)
+	}
+
+	chosen := -1
+	for i := range results {
+		if results[i].Status == NodeApplied || results[i].Status == NodePending {
+			chosen = i
+			break
+		}
+	}
+
+	if failed {
+		err := &ClusterError{Operation: operation, Results: results}
+		if read || !continueOnFailure || chosen < 0 {
+			if read {
+				// a read fails as a whole, with the error of the first failing
+				// node, e.g. ErrNotFound when an object is missing on a node
+				for _, result := range results {
+					if result.Err != nil {
+						return fmt.Errorf("%w (%s)", result.Err, result.Endpoint)
+					}
+				}
+			}
+			return err
+		}
+		log.Printf("[WARN] %s", err)
+	}
+
+	if chosen < 0 {
+		return fmt.Errorf("%s: no node available", operation)
+	}
+
+	if read && v != nil {
+		chosen = driftingNode(bodies, chosen)
+		if chosen != 0 {
+			log.Printf("[WARN] %s returned a different result on %s than on %s", operation, c.nodes[chosen].endpoint, c.nodes[0].endpoint)
+		}
+	}
+
+	return decodeResponse(bodies[chosen], v)
+}
+
+// upsertable reports whether a change failed only because its object
+// already exists, for a create, or is missing, for an update or a delete, on
+// the node req was sent to.
+func upsertable(req *http.Request, err error) bool {
+	if req == nil || !strings.Contains(req.URL.Path, "/services/haproxy/configuration/") {
+		return false
+	}
+	switch req.Method {
+	case "POST":
+		return errors.Is(err, ErrConflict) && !errors.Is(err, ErrVersionMismatch)
+	case "PUT", "DELETE":
+		return errors.Is(err, ErrNotFound)
+	}
+	return false
+}
+
+// upsertDriftedNodes makes the nodes missing an object, or already having
+// it, converge with the nodes on which the change succeeded: a create is
+// sent again as an update, an update as a create, and a delete is already
+// done. When the change succeeded on no node, the object really exists, or
+// is really missing, and the change fails as it would on a single node.
+func (c *Client) upsertDriftedNodes(drifted map[int]*http.Request, body []byte, results []NodeResult, bodies [][]byte, succeeded func(int), fail func(int, error)) {
+	converged := false
+	for i := range results {
+		if results[i].Status == NodeApplied || results[i].Status == NodePending {
+			converged = true
+			break
+		}
+	}
+
+	for i := range c.nodes {
+		nodeReq, ok := drifted[i]
+		if !ok {
+			continue
+		}
+		err := results[i].Err
+		if !converged {
+			fail(i, err)
+			continue
+		}
+
+		if nodeReq.Method == "DELETE" {
+			log.Printf("[WARN] %s: object already missing on %s", nodeReq.Method+" "+nodeReq.URL.Path, c.nodes[i].endpoint)
+			succeeded(i)
+			continue
+		}
+
+		upsertReq, err := upsertRequest(nodeReq, body)
+		if err == nil {
+			log.Printf("[WARN] %s drifted on %s, sending it as %s %s", nodeReq.Method+" "+nodeReq.URL.Path, c.nodes[i].endpoint, upsertReq.Method, upsertReq.URL.Path)
+			bodies[i], err = c.nodes[i].do(upsertReq)
+		}
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		succeeded(i)
+	}
+}
+
+// upsertRequest returns the update of the object req failed to create
+// because it already exists, or the create of the object req failed to
+// update because it is missing.
+func upsertRequest(req *http.Request, body []byte) (*http.Request, error) {
+	u := *req.URL
+	path := u.EscapedPath()
+	method := "PUT"
+	if req.Method == "PUT" {
+		method = "POST"
+		path = path[:strings.LastIndex(path, "/")]
+	} else {
+		// objects are identified by their name, or their index for the
+		// ones ordered in their section such as rules
+		object := map[string]interface{}{}
+		if err := json.Unmarshal(body, &object); err != nil {
+			return nil, err
+		}
+		id := ""
+		switch {
+		case object["name"] != nil:
+			id = fmt.Sprint(object["name"])
+		case object["index"] != nil:
+			id = fmt.Sprint(object["index"])
+		default:
+			return nil, fmt.Errorf("cannot identify the object created by %s %s", req.Method, req.URL.Path)
+		}
+		path += "/" + escapePathSegment(id)
+	}
+
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return nil, err
+	}
+	u.Path, u.RawPath = unescaped, path
+
+	upsertReq, err := http.NewRequestWithContext(req.Context(), method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	upsertReq.Header = req.Header.Clone()
+	return upsertReq, nil
+}
+
+// nodeRequest clones req for the i-th node, pointing at its own transaction
+// and configuration version.
+func (c *Client) nodeRequest(req *http.Request, i int, tx *clusterTransaction, body []byte) (*http.Request, error) {
+	node := c.nodes[i]
+
+	u, err := url.Parse(node.base_url + strings.TrimPrefix(req.URL.String(), c.base_url))
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	if tx != nil {
+		query.Set("transaction_id", tx.ids[i])
+	} else if query.Get("version") != "" {
+		configuration, err := node.GetConfiguration(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		query.Set("version", strconv.Itoa(configuration.Version))
+	}
+	u.RawQuery = query.Encode()
+
+	nodeReq, err := http.NewRequestWithContext(req.Context(), req.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	nodeReq.Header = req.Header.Clone()
+	return nodeReq, nil
+}
+
+// driftingNode returns the first node whose response differs from the one
+// of the reference node, ignoring configuration versions which are specific
+// to each node, or the reference node itself.
+func driftingNode(bodies [][]byte, reference int) int {
+	expected := normalizeResponse(bodies[reference])
+	for i := reference + 1; i < len(bodies); i++ {
+		if bodies[i] == nil {
+			continue
+		}
+		if !reflect.DeepEqual(expected, normalizeResponse(bodies[i])) {
+			return i
+		}
+	}
+	return reference
+}
+
+func normalizeResponse(body []byte) interface{} {
+	var content interface{}
+	if err := json.Unmarshal(body, &content); err != nil {
+		return string(body)
+	}
+	if object, ok := content.(map[string]interface{}); ok {
+		delete(object, "_version")
+	}
+	return content
+}
+
+// createClusterTransaction opens a transaction on every node, each on its
+// own current configuration version.
+func (c *Client) createClusterTransaction(ctx context.Context) (*models.Transaction, error) {
+	tx := &clusterTransaction{
+		ids:      make([]string, len(c.nodes)),
+		versions: make([]int, len(c.nodes)),
+		failures: make([]error, len(c.nodes)),
+	}
+	results := make([]NodeResult, len(c.nodes))
+
+	opened, failed := 0, false
+	for i, node := range c.nodes {
+		results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeSkipped}
+		if failed && c.PartialFailurePolicy != PartialFailureContinue {
+			continue
+		}
+
+		configuration, err := node.GetConfiguration(ctx)
+		if err == nil {
+			var transaction *models.Transaction
+			transaction, err = node.CreateTransaction(ctx, configuration.Version)
+			if err == nil {
+				tx.ids[i] = transaction.Id
+				tx.versions[i] = configuration.Version
+			}
+		}
+		if err != nil {
+			results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeFailed, Err: err}
+			failed = true
+			continue
+		}
+		results[i].Status = NodePending
+		opened++
+	}
+
+	if failed {
+		err := &ClusterError{Operation: "opening transaction", Results: results}
+		if c.PartialFailurePolicy != PartialFailureContinue || opened == 0 {
+			c.rollbackClusterTransaction(ctx, tx)
+			return nil, err
+		}
+		log.Printf("[WARN] %s", err)
+	}
+
+	id := strings.Join(tx.ids, ",")
+	c.clusterMu.Lock()
+	c.clusterTransactions[id] = tx
+	c.clusterMu.Unlock()
+
+	return &models.Transaction{Id: id, Status: "in_progress"}, nil
+}
+
+// commitClusterTransaction commits the transaction of every node, applying
+// the partial failure policy when a node fails to commit or a change failed
+// on it.
+func (c *Client) commitClusterTransaction(ctx context.Context, transactionId string) (*models.Transaction, error) {
+	tx, ok := c.dropClusterTransaction(transactionId)
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction %s", transactionId)
+	}
+
+	// the configuration of each node is saved before committing, to be
+	// restored if a later node fails. Nodes not committed yet only get their
+	// transaction deleted.
+	saved := make([]*models.Configuration, len(c.nodes))
+	if c.PartialFailurePolicy == PartialFailureRollback {
+		for i, node := range c.nodes {
+			if tx.ids[i] == "" {
+				continue
+			}
+			configuration, err := node.GetRawConfiguration(ctx)
+			if err != nil {
+				c.rollbackClusterTransaction(ctx, tx)
+				return nil, err
+			}
+			saved[i] = configuration
+		}
+	}
+
+	results := make([]NodeResult, len(c.nodes))
+	applied, failed := 0, false
+	for i, node := range c.nodes {
+		results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeSkipped}
+		if tx.ids[i] == "" {
+			continue
+		}
+		if tx.failures[i] != nil {
+			results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeFailed, Err: tx.failures[i]}
+			failed = true
+			_ = node.RollbackTransaction(ctx, tx.ids[i])
+			continue
+		}
+		if failed && c.PartialFailurePolicy != PartialFailureContinue {
+			_ = node.RollbackTransaction(ctx, tx.ids[i])
+			continue
+		}
+
+		if _, err := node.CommitTransaction(ctx, tx.ids[i]); err != nil {
+			results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeFailed, Err: err}
+			failed = true
+			_ = node.RollbackTransaction(ctx, tx.ids[i])
+			continue
+		}
+		results[i].Status = NodeApplied
+		applied++
+	}
+
+	if !failed {
+		return &models.Transaction{Id: transactionId, Status: "success"}, nil
+	}
+
+	switch c.PartialFailurePolicy {
+	case PartialFailureRollback:
+		// a committed node is only restored while its configuration is still
+		// the one committed, a change made since then is never clobbered
+		for i, node := range c.nodes {
+			if results[i].Status != NodeApplied {
+				continue
+			}
+			if saved[i].Version != tx.versions[i] {
+				results[i].Err = fmt.Errorf("not rolled back, the configuration changed before the commit")
+				continue
+			}
+			err := node.RestoreConfiguration(ctx, saved[i].Data, tx.versions[i]+1)
+			if errors.Is(err, ErrConflict) {
+				results[i].Err = fmt.Errorf("not rolled back, the configuration changed since the commit: %w", err)
+				continue
+			}
+			if err != nil {
+				results[i].Err = fmt.Errorf("rolling back failed: %w", err)
+				continue
+			}
+			results[i].Status = NodeRolledBack
+		}
+	case PartialFailureContinue:
+		if applied > 0 {
+			log.Printf("[WARN] %s", &ClusterError{Operation: "committing transaction", Results: results})
+			return &models.Transaction{Id: transactionId, Status: "success"}, nil
+		}
+	}
+
+	return nil, &ClusterError{Operation: "committing transaction", Results: results}
+}
+
+// rollbackClusterTransaction deletes the transaction of every node.
+func (c *Client) rollbackClusterTransaction(ctx context.Context, tx *clusterTransaction) {
+	for i, node := range c.nodes {
+		if tx.ids[i] != "" {
+			_ = node.RollbackTransaction(ctx, tx.ids[i])
+		}
+	}
+}
+
+// deleteClusterTransaction deletes the transaction of every node.
+func (c *Client) deleteClusterTransaction(ctx context.Context, transactionId string) error {
+	tx, ok := c.dropClusterTransaction(transactionId)
+	if !ok {
+		return ErrNotFound
+	}
+
+	results := make([]NodeResult, len(c.nodes))
+	failed := false
+	for i, node := range c.nodes {
+		results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeSkipped}
+		if tx.ids[i] == "" {
+			continue
+		}
+		if err := node.RollbackTransaction(ctx, tx.ids[i]); err != nil {
+			results[i] = NodeResult{Endpoint: node.endpoint, Status: NodeFailed, Err: err}
+			failed = true
+			continue
+		}
+		results[i].Status = NodeApplied
+	}
+
+	if failed {
+		return &ClusterError{Operation: "deleting transaction", Results: results}
+	}
+	return nil
+}
+
+// detectClusterAPIVersion detects the API version of every node, which must
+// all serve the same one.
+func (c *Client) detectClusterAPIVersion(ctx context.Context) (string, error) {
+	version := ""
+	for _, node := range c.nodes {
+		nodeVersion, err := node.DetectAPIVersion(ctx)
+		if err != nil {
+			return "", err
+		}
+		if version != "" && nodeVersion != version {
+			return "", fmt.Errorf("nodes serve different Dataplane API versions: %s serves %s, %s serves %s", c.nodes[0].endpoint, version, node.endpoint, nodeVersion)
+		}
+		version = nodeVersion
+	}
+
+	c.SetAPIVersion(version)
+	return version, nil
+}
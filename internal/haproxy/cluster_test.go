@@ -0,0 +1,329 @@
+package haproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// fakeNodeAPI is one node of a cluster storing the backends committed
+// through its transactions.
+type fakeNodeAPI struct {
+	name string
+
+	mu        sync.Mutex
+	version   int
+	backends  map[string]string
+	pending   map[string]string
+	committed []string
+	deleted   []string
+	restored  []string
+	updated   int
+
+	failChange        bool
+	failCommit        bool
+	changeAfterCommit bool
+}
+
+func newFakeNodeAPI(name string) *fakeNodeAPI {
+	return &fakeNodeAPI{name: name, version: 1, backends: map[string]string{}}
+}
+
+func (f *fakeNodeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	transactionId := "tx-" + f.name
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/configuration/raw"):
+		fmt.Fprintf(w, `{"_version": %d, "data": "config of %s"}`, f.version, f.name)
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/configuration/raw"):
+		if r.URL.Query().Get("version") != strconv.Itoa(f.version) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"code": 409, "message": "version mismatch"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.restored = append(f.restored, string(body))
+		f.version++
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/transactions"):
+		f.pending = map[string]string{}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"_version": %d, "id": %q, "status": "in_progress"}`, f.version, transactionId)
+	case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/transactions/"+transactionId):
+		if f.failCommit {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"code": 500, "message": "reload failed"}`))
+			return
+		}
+		for name, balance := range f.pending {
+			f.backends[name] = balance
+		}
+		f.version++
+		if f.changeAfterCommit {
+			// another change lands right after the commit
+			f.version++
+		}
+		f.committed = append(f.committed, transactionId)
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, `{"_version": %d, "id": %q, "status": "success"}`, f.version, transactionId)
+	case r.Method == "DELETE" && strings.HasSuffix(r.URL.Path, "/transactions/"+transactionId):
+		f.deleted = append(f.deleted, transactionId)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/backends"):
+		if r.URL.Query().Get("transaction_id") != transactionId {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "transaction not found"}`))
+			return
+		}
+		if f.failChange {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": 400, "message": "invalid backend"}`))
+			return
+		}
+		if _, ok := f.backends["test"]; ok {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"code": 409, "message": "backend test already exists"}`))
+			return
+		}
+		f.pending["test"] = "roundrobin"
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name": "test", "balance": {"algorithm": "roundrobin"}}`))
+	case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/backends/test"):
+		if _, ok := f.backends["test"]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "missing object"}`))
+			return
+		}
+		backend := models.Backend{}
+		json.NewDecoder(r.Body).Decode(&backend)
+		f.pending["test"] = backend.Balance.Algorithm
+		f.updated++
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(backend)
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/backends/test"):
+		balance, ok := f.backends["test"]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "missing object"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"_version": %d, "data": {"name": "test", "balance": {"algorithm": %q}}}`, f.version, balance)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestCluster(t *testing.T, policy string, nodes ...*fakeNodeAPI) *Client {
+	clients := []*Client{}
+	for _, node := range nodes {
+		clients = append(clients, newTestClient(t, node))
+	}
+	cluster := NewCluster(clients, policy)
+	cluster.TransactionBatchWindow = 0
+	return cluster
+}
+
+func createTestBackend(client *Client) error {
+	return client.WithTransaction(context.Background(), func(transactionId string) error {
+		_, err := client.CreateBackend(context.Background(), transactionId, models.Backend{Name: "test"})
+		return err
+	})
+}
+
+func TestClusterAppliesChangesToEveryNode(t *testing.T) {
+	first, second := newFakeNodeAPI("a"), newFakeNodeAPI("b")
+	second.version = 7
+	client := newTestCluster(t, PartialFailureFail, first, second)
+
+	if err := createTestBackend(client); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, node := range []*fakeNodeAPI{first, second} {
+		if len(node.committed) != 1 || node.committed[0] != "tx-"+node.name {
+			t.Fatalf("expected node %s to commit its own transaction, got %v", node.name, node.committed)
+		}
+		if node.backends["test"] != "roundrobin" {
+			t.Fatalf("expected the backend to be created on node %s", node.name)
+		}
+	}
+}
+
+func TestClusterReadDetectsDrift(t *testing.T) {
+	first, second := newFakeNodeAPI("a"), newFakeNodeAPI("b")
+	first.backends["test"] = "roundrobin"
+	second.backends["test"] = "leastconn"
+	second.version = 3
+	client := newTestCluster(t, PartialFailureFail, first, second)
+
+	backend, err := client.GetBackend(context.Background(), models.Backend{Name: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if backend.Balance.Algorithm != "leastconn" {
+		t.Fatalf("expected the drifting node to be returned, got %s", backend.Balance.Algorithm)
+	}
+
+	delete(second.backends, "test")
+	_, err = client.GetBackend(context.Background(), models.Backend{Name: "test"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected an object missing on a node to be not found, got %v", err)
+	}
+}
+
+func TestClusterRepairsObjectMissingOnANode(t *testing.T) {
+	first, second := newFakeNodeAPI("a"), newFakeNodeAPI("b")
+	first.backends["test"] = "roundrobin"
+	client := newTestCluster(t, PartialFailureFail, first, second)
+
+	_, err := client.GetBackend(context.Background(), models.Backend{Name: "test"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the backend to be planned for creation, got %v", err)
+	}
+
+	// the re-apply creates the backend, updating it where it already exists
+	backend := models.Backend{Name: "test", Balance: &models.Balance{Algorithm: "roundrobin"}}
+	err = client.Retry(context.Background(), func() error {
+		return client.WithTransaction(context.Background(), func(transactionId string) error {
+			_, err := client.CreateBackend(context.Background(), transactionId, backend)
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if first.updated != 1 || second.backends["test"] != "roundrobin" {
+		t.Fatalf("expected the backend updated on a and created on b, got %d updates and %v", first.updated, second.backends)
+	}
+
+	if _, err := client.GetBackend(context.Background(), models.Backend{Name: "test"}); err != nil {
+		t.Fatalf("expected the backend found on every node, got %v", err)
+	}
+}
+
+func TestClusterUpdateCreatesObjectMissingOnANode(t *testing.T) {
+	first, second := newFakeNodeAPI("a"), newFakeNodeAPI("b")
+	first.backends["test"] = "roundrobin"
+	client := newTestCluster(t, PartialFailureFail, first, second)
+
+	backend := models.Backend{Name: "test", Balance: &models.Balance{Algorithm: "roundrobin"}}
+	err := client.WithTransaction(context.Background(), func(transactionId string) error {
+		_, err := client.UpdateBackend(context.Background(), transactionId, backend)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if second.backends["test"] != "roundrobin" {
+		t.Fatalf("expected the backend created on b, got %v", second.backends)
+	}
+}
+
+func TestClusterCreateConflictingOnEveryNodeFails(t *testing.T) {
+	first, second := newFakeNodeAPI("a"), newFakeNodeAPI("b")
+	first.backends["test"] = "roundrobin"
+	second.backends["test"] = "roundrobin"
+	client := newTestCluster(t, PartialFailureFail, first, second)
+
+	err := createTestBackend(client)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a backend existing everywhere to conflict, got %v", err)
+	}
+	if first.updated != 0 || second.updated != 0 {
+		t.Fatal("expected no node to be updated")
+	}
+}
+
+func TestClusterPartialFailurePolicies(t *testing.T) {
+	tests := []struct {
+		policy        string
+		failChange    bool
+		wantErr       bool
+		wantCommitted int
+		wantRestored  int
+	}{
+		{policy: PartialFailureFail, wantErr: true, wantCommitted: 1},
+		{policy: PartialFailureRollback, wantErr: true, wantCommitted: 1, wantRestored: 1},
+		{policy: PartialFailureContinue, wantCommitted: 1},
+		{policy: PartialFailureContinue, failChange: true, wantCommitted: 1},
+		{policy: PartialFailureFail, failChange: true, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/failChange=%t", test.policy, test.failChange), func(t *testing.T) {
+			first, second := newFakeNodeAPI("a"), newFakeNodeAPI("b")
+			second.failCommit = !test.failChange
+			second.failChange = test.failChange
+			client := newTestCluster(t, test.policy, first, second)
+
+			err := createTestBackend(client)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(first.committed) != test.wantCommitted {
+				t.Fatalf("expected %d commits on the first node, got %d", test.wantCommitted, len(first.committed))
+			}
+			if len(first.restored) != test.wantRestored {
+				t.Fatalf("expected %d restores on the first node, got %d", test.wantRestored, len(first.restored))
+			}
+			if test.wantRestored > 0 && first.restored[0] != "config of a" {
+				t.Fatalf("expected the previous configuration to be restored, got %q", first.restored[0])
+			}
+			if len(second.committed) != 0 || len(second.deleted) != 1 {
+				t.Fatalf("expected the failing node transaction to be deleted, got committed=%v deleted=%v", second.committed, second.deleted)
+			}
+
+			var clusterErr *ClusterError
+			if test.wantErr && !errors.As(err, &clusterErr) {
+				t.Fatalf("expected a ClusterError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestClusterRollbackKeepsChangesMadeSinceTheCommit(t *testing.T) {
+	first, second := newFakeNodeAPI("a"), newFakeNodeAPI("b")
+	first.changeAfterCommit = true
+	second.failCommit = true
+	client := newTestCluster(t, PartialFailureRollback, first, second)
+
+	err := createTestBackend(client)
+	var clusterErr *ClusterError
+	if !errors.As(err, &clusterErr) {
+		t.Fatalf("expected a ClusterError, got %v", err)
+	}
+	if len(first.restored) != 0 {
+		t.Fatalf("expected the node changed since the commit not to be restored, got %v", first.restored)
+	}
+	if clusterErr.Results[0].Status != NodeApplied || !strings.Contains(clusterErr.Results[0].Err.Error(), "not rolled back") {
+		t.Fatalf("expected the node reported as not rolled back, got %+v", clusterErr.Results[0])
+	}
+}
+
+func TestClusterErrorIsRetryableOnlyWhenAppliedNowhere(t *testing.T) {
+	conflict := &APIError{StatusCode: http.StatusConflict, Message: "version mismatch"}
+	err := &ClusterError{Results: []NodeResult{
+		{Endpoint: "a", Status: NodeFailed, Err: conflict},
+		{Endpoint: "b", Status: NodeSkipped},
+	}}
+	if !IsRetryable(err) {
+		t.Fatal("expected a conflict applied nowhere to be retryable")
+	}
+
+	err.Results[1].Status = NodeApplied
+	if IsRetryable(err) {
+		t.Fatal("expected a change applied to a node not to be retried")
+	}
+}
@@ -1,14 +1,27 @@
 package haproxy
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
 )
 
-func (c *Client) GetConfiguration() (*models.Configuration, error) {
+func (c *Client) GetConfiguration(ctx context.Context) (*models.Configuration, error) {
+	if len(c.nodes) > 0 {
+		// every node has its own version, the one of the first node stands
+		// for the cluster
+		return c.nodes[0].GetConfiguration(ctx)
+	}
+
+	if c.apiVersion == APIVersionV3 {
+		return c.getConfigurationVersion(ctx)
+	}
+
 	url := c.base_url + "/services/haproxy/configuration/raw"
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -24,3 +37,66 @@ func (c *Client) GetConfiguration() (*models.Configuration, error) {
 
 	return &res, nil
 }
+
+// getConfigurationVersion returns the current configuration version without
+// its content. v3 serves the raw configuration as plain text and exposes its
+// version through a dedicated endpoint.
+func (c *Client) getConfigurationVersion(ctx context.Context) (*models.Configuration, error) {
+	url := c.base_url + "/services/haproxy/configuration/version"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.Configuration{}
+	if err := c.sendRequest(req, &res.Version); err != nil {
+		return nil, err
+	}
+
+	if res.Version == 0 {
+		res.Version = 1
+	}
+
+	return &res, nil
+}
+
+// GetRawConfiguration returns the current configuration with its content.
+func (c *Client) GetRawConfiguration(ctx context.Context) (*models.Configuration, error) {
+	if c.apiVersion != APIVersionV3 {
+		return c.GetConfiguration(ctx)
+	}
+
+	res, err := c.getConfigurationVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.base_url+"/services/haproxy/configuration/raw", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	data, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Data = string(data)
+
+	return res, nil
+}
+
+// RestoreConfiguration replaces the whole configuration with data and
+// reloads HAProxy, as long as the configuration is still at version. It
+// fails with a conflict otherwise.
+func (c *Client) RestoreConfiguration(ctx context.Context, data string, version int) error {
+	url := c.base_url + "/services/haproxy/configuration/raw?version=" + strconv.Itoa(version)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	_, err = c.do(req)
+	return err
+}
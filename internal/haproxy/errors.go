@@ -0,0 +1,85 @@
+package haproxy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+var (
+	ErrNotFound      = errors.New("NotFound")
+	ErrBadRequest    = errors.New("BadRequest")
+	ErrUnauthorized  = errors.New("Unauthorized")
+	ErrForbidden     = errors.New("Forbidden")
+	ErrNotAcceptable = errors.New("NotAcceptable")
+	ErrConflict      = errors.New("Conflict")
+	// ErrVersionMismatch is the conflict of a change made against an outdated
+	// configuration version, as opposed to e.g. an object already existing.
+	ErrVersionMismatch = errors.New("VersionMismatch")
+)
+
+// versionMismatchRegex matches the messages of the Data Plane API rejecting a
+// change because the configuration version moved, e.g. "version mismatch"
+// or "Version in configuration file is 4, given version is 3".
+var versionMismatchRegex = regexp.MustCompile(`(?i)version mismatch|given version|version .*outdated|outdated .*version`)
+
+// APIError is returned for every non-2xx response of the Data Plane API. It
+// matches the sentinel error of its status code with errors.Is, e.g.
+// errors.Is(err, ErrConflict) for any 409, and errors.Is(err,
+// ErrVersionMismatch) only for the ones due to an outdated version.
+type APIError struct {
+	StatusCode int
+	// Code is the error code reported by the Data Plane API, usually the
+	// status code, or 0 when the body isn't a Data Plane API error.
+	Code    int
+	Message string
+	Method  string
+	Path    string
+	Body    []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%s %s returned %d)", e.Message, e.Method, e.Path, e.StatusCode)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotAcceptable:
+		return e.StatusCode == http.StatusNotAcceptable
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrVersionMismatch:
+		return e.StatusCode == http.StatusConflict && versionMismatchRegex.MatchString(e.Message)
+	}
+	return false
+}
+
+var validationFieldRegex = regexp.MustCompile(`(?m)^([A-Za-z0-9_.\-]+) in (?:body|query|path)\b`)
+
+// Field returns the field named by the first validation failure of the
+// message, e.g. "balance.algorithm", or an empty string.
+func (e *APIError) Field() string {
+	match := validationFieldRegex.FindStringSubmatch(e.Message)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
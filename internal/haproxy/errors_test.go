@@ -0,0 +1,75 @@
+package haproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func TestSendRequestReturnsAPIError(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"code": 422, "message": "validation failure list:\nbalance.algorithm in body should be one of [roundrobin static-rr leastconn]"}`))
+	}))
+
+	_, err := client.GetBackend(context.Background(), models.Backend{Name: "test"})
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected an APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != 422 {
+		t.Fatalf("unexpected status %d and code %d", apiErr.StatusCode, apiErr.Code)
+	}
+	if apiErr.Method != "GET" || apiErr.Path != "/v2/services/haproxy/configuration/backends/test" {
+		t.Fatalf("unexpected request %s %s", apiErr.Method, apiErr.Path)
+	}
+	if len(apiErr.Body) == 0 {
+		t.Fatal("expected the raw body to be kept")
+	}
+	if !errors.Is(err, ErrBadRequest) || errors.Is(err, ErrConflict) {
+		t.Fatalf("unexpected sentinel matching for %v", err)
+	}
+	if field := apiErr.Field(); field != "balance.algorithm" {
+		t.Fatalf("expected the failing field to be balance.algorithm, got %q", field)
+	}
+}
+
+func TestSendRequestReturnsAPIErrorForUnknownBody(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>bad gateway</html>"))
+	}))
+
+	_, err := client.GetBackend(context.Background(), models.Backend{Name: "test"})
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected an APIError, got %T: %v", err, err)
+	}
+	if apiErr.Code != 0 || apiErr.Message != "unknown error, status code: 502" || string(apiErr.Body) != "<html>bad gateway</html>" {
+		t.Fatalf("unexpected error %#v", apiErr)
+	}
+}
+
+func TestAPIErrorMatchesSentinels(t *testing.T) {
+	cases := map[int]error{
+		http.StatusNotFound:      ErrNotFound,
+		http.StatusBadRequest:    ErrBadRequest,
+		http.StatusUnauthorized:  ErrUnauthorized,
+		http.StatusForbidden:     ErrForbidden,
+		http.StatusNotAcceptable: ErrNotAcceptable,
+		http.StatusConflict:      ErrConflict,
+	}
+
+	for status, sentinel := range cases {
+		err := error(&APIError{StatusCode: status})
+		if !errors.Is(err, sentinel) {
+			t.Errorf("expected status %d to match %s", status, sentinel)
+		}
+	}
+}
@@ -2,31 +2,32 @@ package haproxy
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
 )
 
-func (c *Client) GetFrontend(frontend models.Frontend) (*models.Frontend, error) {
-	url := c.base_url + "/services/haproxy/configuration/frontends/" + frontend.Name
-	req, err := http.NewRequest("GET", url, nil)
+func (c *Client) GetFrontend(ctx context.Context, frontend models.Frontend) (*models.Frontend, error) {
+	url := c.base_url + "/services/haproxy/configuration/frontends/" + escapePathSegment(frontend.Name)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	res := models.GetFrontend{}
-	if err := c.sendRequest(req, &res); err != nil {
+	res := models.Frontend{}
+	if err := c.sendConfigurationRequest(req, &res); err != nil {
 		return nil, err
 	}
 
-	return &res.Data, nil
+	return &res, nil
 }
 
-func (c *Client) CreateFrontend(transactionId string, frontend models.Frontend) (*models.Frontend, error) {
-	url := c.base_url + "/services/haproxy/configuration/frontends?transaction_id=" + transactionId
+func (c *Client) CreateFrontend(ctx context.Context, transactionId string, frontend models.Frontend) (*models.Frontend, error) {
+	url := c.base_url + "/services/haproxy/configuration/frontends" + encodeQuery(transactionQuery(transactionId))
 	bodyStr, _ := json.Marshal(frontend)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyStr))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
 	if err != nil {
 		return nil, err
 	}
@@ -41,10 +42,10 @@ func (c *Client) CreateFrontend(transactionId string, frontend models.Frontend)
 	return &res, nil
 }
 
-func (c *Client) UpdateFrontend(transactionId string, frontend models.Frontend) (*models.Frontend, error) {
-	url := c.base_url + "/services/haproxy/configuration/frontends/" + frontend.Name + "?transaction_id=" + transactionId
+func (c *Client) UpdateFrontend(ctx context.Context, transactionId string, frontend models.Frontend) (*models.Frontend, error) {
+	url := c.base_url + "/services/haproxy/configuration/frontends/" + escapePathSegment(frontend.Name) + encodeQuery(transactionQuery(transactionId))
 	bodyStr, _ := json.Marshal(frontend)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(bodyStr))
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +60,9 @@ func (c *Client) UpdateFrontend(transactionId string, frontend models.Frontend)
 	return &res, nil
 }
 
-func (c *Client) DeleteFrontend(transactionId string, frontend models.Frontend) error {
-	url := c.base_url + "/services/haproxy/configuration/frontends/" + frontend.Name + "?transaction_id=" + transactionId
-	req, err := http.NewRequest("DELETE", url, nil)
+func (c *Client) DeleteFrontend(ctx context.Context, transactionId string, frontend models.Frontend) error {
+	url := c.base_url + "/services/haproxy/configuration/frontends/" + escapePathSegment(frontend.Name) + encodeQuery(transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
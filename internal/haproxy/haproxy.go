@@ -1,10 +1,15 @@
 package haproxy
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -12,24 +17,104 @@ type Client struct {
 	username   string
 	password   string
 	base_url   string
+	endpoint   string
+	apiVersion string
 	HTTPClient *http.Client
+
+	// TransactionBatchWindow is how long a transaction stays open for other
+	// changes once its last change is done, before being committed.
+	TransactionBatchWindow time.Duration
+
+	// RetryAttempts and RetryMaxWait bound the retries of Retry.
+	RetryAttempts uint
+	RetryMaxWait  time.Duration
+
+	batchMu sync.Mutex
+	batch   *transactionBatch
+
+	// persistMu serializes the writes of runtime ACL files to the storage,
+	// so the last write holds every change.
+	persistMu sync.Mutex
+
+	// aclStorage is the storage file each ACL file is persisted to, as
+	// planned by the resources using it, see ClaimAclStorage.
+	aclStorageMu sync.Mutex
+	aclStorage   map[string]string
+
+	// nodes are the HAProxy instances every change is applied to when the
+	// client manages a cluster, see NewCluster.
+	nodes                []*Client
+	PartialFailurePolicy string
+	clusterMu            sync.Mutex
+	clusterTransactions  map[string]*clusterTransaction
 }
 
-var ErrNotFound = errors.New("NotFound")
+const DefaultAPIVersion = APIVersionV2
+
+// NewClient returns a client of the Data Plane API at server_url, which is
+// either:
+//   - host:port, reached over https, or plain http if insecure is true
+//   - a full URL such as https://lb.example.com/dataplane, for an API behind
+//     a reverse proxy under a path prefix
+//   - unix:///path/to/dataplane.sock, for an API listening on a Unix socket
+//
+// apiVersion is the API version prefix appended to it, e.g. "v2". With
+// APIVersionAuto, the client talks v2 until DetectAPIVersion is called.
+func NewClient(username string, password string, server_url string, insecure bool, apiVersion string) (*Client, error) {
+	if apiVersion == "" || apiVersion == APIVersionAuto {
+		apiVersion = DefaultAPIVersion
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 
-func NewClient(username string, password string, server_url string, insecure bool) *Client {
-	scheme := "https"
-	if insecure {
-		scheme = "http"
+	var endpoint string
+	switch {
+	case strings.HasPrefix(server_url, "unix://"):
+		socket := strings.TrimPrefix(server_url, "unix://")
+		if socket == "" {
+			return nil, fmt.Errorf("invalid server address %s: missing socket path", server_url)
+		}
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		// the host is ignored, every connection goes to the socket
+		endpoint = "http://unix"
+	case strings.Contains(server_url, "://"):
+		u, err := url.Parse(server_url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server address %s: %s", server_url, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid server address %s: scheme must be http, https or unix", server_url)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid server address %s: missing host", server_url)
+		}
+		u.RawQuery = ""
+		u.Fragment = ""
+		endpoint = strings.TrimRight(u.String(), "/")
+	default:
+		scheme := "https"
+		if insecure {
+			scheme = "http"
+		}
+		endpoint = scheme + "://" + server_url
 	}
-	return &Client{
+
+	client := &Client{
 		username: username,
 		password: password,
 		HTTPClient: &http.Client{
-			Timeout: 5 * time.Minute,
+			Timeout:   5 * time.Minute,
+			Transport: transport,
 		},
-		base_url: scheme + "://" + server_url + "/v2",
+		endpoint:      endpoint,
+		RetryAttempts: DefaultRetryAttempts,
+		RetryMaxWait:  DefaultRetryMaxWait,
 	}
+	client.SetAPIVersion(apiVersion)
+	return client, nil
 }
 
 type errorResponse struct {
@@ -38,43 +123,74 @@ type errorResponse struct {
 }
 
 func (c *Client) sendRequest(req *http.Request, v interface{}) error {
-	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("Authorization", "Basic "+basicAuth(c.username, c.password))
+	if len(c.nodes) > 0 {
+		return c.sendClusterRequest(req, v)
+	}
 
-	res, err := c.HTTPClient.Do(req)
+	body, err := c.do(req)
 	if err != nil {
 		return err
 	}
 
-	defer res.Body.Close()
+	return decodeResponse(body, v)
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		// Latest version of haproxy API return 404 now instead of 204 before.
-		return ErrNotFound
+// decodeResponse decodes the JSON body of a response into v, or copies it as
+// is when v is a *[]byte, for endpoints serving files.
+func decodeResponse(body []byte, v interface{}) error {
+	if len(body) == 0 || v == nil {
+		return nil
 	}
 
-	// Try to unmarshall into errorResponse
-	if res.StatusCode >= 300 {
-		var errRes errorResponse
-		if err = json.NewDecoder(res.Body).Decode(&errRes); err == nil {
-			return errors.New(errRes.Message)
-		}
-
-		return fmt.Errorf("unknown error, status code: %d", res.StatusCode)
+	if raw, ok := v.(*[]byte); ok {
+		*raw = body
+		return nil
 	}
 
-	if res.StatusCode == http.StatusNoContent {
-		return nil
+	return json.Unmarshal(body, &v)
+}
+
+// do sends req and returns the body of the response, or an APIError for a
+// non-2xx response.
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json; charset=utf-8")
 	}
+	req.Header.Set("Authorization", "Basic "+basicAuth(c.username, c.password))
 
-	if v == nil {
-		return nil
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
 
-	if err = json.NewDecoder(res.Body).Decode(&v); err != nil {
-		return err
+	defer res.Body.Close()
+
+	// Latest version of haproxy API return 404 now instead of 204 before, so
+	// a missing object is reported as an APIError matching ErrNotFound.
+	if res.StatusCode >= 300 {
+		body, _ := io.ReadAll(res.Body)
+		apiErr := &APIError{
+			StatusCode: res.StatusCode,
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			Body:       body,
+		}
+
+		// Try to unmarshall into errorResponse
+		var errRes errorResponse
+		if err = json.Unmarshal(body, &errRes); err == nil && errRes.Message != "" {
+			apiErr.Code = errRes.Code
+			apiErr.Message = errRes.Message
+		} else {
+			apiErr.Message = fmt.Sprintf("unknown error, status code: %d", res.StatusCode)
+		}
+
+		return nil, apiErr
 	}
 
-	return nil
+	if res.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
 
+	return io.ReadAll(res.Body)
 }
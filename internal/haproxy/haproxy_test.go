@@ -0,0 +1,71 @@
+package haproxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientBaseURL(t *testing.T) {
+	cases := []struct {
+		serverURL  string
+		insecure   bool
+		apiVersion string
+		expected   string
+	}{
+		{"localhost:5555", false, "", "https://localhost:5555/v2"},
+		{"localhost:5555", true, "v3", "http://localhost:5555/v3"},
+		{"https://lb.example.com/dataplane/", true, "v2", "https://lb.example.com/dataplane/v2"},
+		{"http://10.0.0.1:5555", false, "v2", "http://10.0.0.1:5555/v2"},
+		{"unix:///var/run/dataplane.sock", false, "v3", "http://unix/v3"},
+	}
+
+	for _, c := range cases {
+		client, err := NewClient("admin", "adminpwd", c.serverURL, c.insecure, c.apiVersion)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", c.serverURL, err)
+		}
+		if client.base_url != c.expected {
+			t.Errorf("NewClient(%s) base url = %s, expected %s", c.serverURL, client.base_url, c.expected)
+		}
+	}
+}
+
+func TestNewClientRejectsInvalidAddresses(t *testing.T) {
+	for _, serverURL := range []string{"ftp://localhost:5555", "https://", "unix://"} {
+		if _, err := NewClient("admin", "adminpwd", serverURL, false, ""); err == nil {
+			t.Errorf("expected an error for %s", serverURL)
+		}
+	}
+}
+
+func TestNewClientUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "dataplane.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets not supported: %s", err)
+	}
+
+	var path string
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	client, err := NewClient("admin", "adminpwd", "unix://"+socket, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.TestApiCall(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if path != "/v2/services/haproxy/stats/native" {
+		t.Fatalf("unexpected request path %s", path)
+	}
+}
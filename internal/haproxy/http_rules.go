@@ -0,0 +1,49 @@
+package haproxy
+
+import (
+	"context"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func (c *Client) GetHttpRequestRules(ctx context.Context, transactionId string, parentType string, parentName string) ([]models.HttpRule, error) {
+	return c.getHttpRules(ctx, transactionId, "http_request_rules", parentType, parentName)
+}
+
+func (c *Client) ReplaceHttpRequestRules(ctx context.Context, transactionId string, parentType string, parentName string, rules []models.HttpRule) error {
+	return c.replaceHttpRules(ctx, transactionId, "http_request_rules", parentType, parentName, rules)
+}
+
+func (c *Client) GetHttpResponseRules(ctx context.Context, transactionId string, parentType string, parentName string) ([]models.HttpRule, error) {
+	return c.getHttpRules(ctx, transactionId, "http_response_rules", parentType, parentName)
+}
+
+func (c *Client) ReplaceHttpResponseRules(ctx context.Context, transactionId string, parentType string, parentName string, rules []models.HttpRule) error {
+	return c.replaceHttpRules(ctx, transactionId, "http_response_rules", parentType, parentName, rules)
+}
+
+func (c *Client) getHttpRules(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string) ([]models.HttpRule, error) {
+	res := []models.HttpRule{}
+	if err := c.getRules(ctx, transactionId, endpoint, parentType, parentName, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) replaceHttpRules(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string, rules []models.HttpRule) error {
+	existing, err := c.getHttpRules(ctx, transactionId, endpoint, parentType, parentName)
+	if err != nil {
+		return err
+	}
+
+	list := make([]interface{}, 0, len(rules))
+	for i := range rules {
+		index := i
+		rule := rules[i]
+		rule.Index = &index
+		list = append(list, rule)
+	}
+
+	return c.replaceRules(ctx, transactionId, endpoint, parentType, parentName, len(existing), list)
+}
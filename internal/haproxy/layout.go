@@ -0,0 +1,163 @@
+package haproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// APIVersionAuto makes DetectAPIVersion pick the newest version the Data
+	// Plane API supports.
+	APIVersionAuto = "auto"
+	APIVersionV2   = "v2"
+	APIVersionV3   = "v3"
+)
+
+// The Data Plane API versions differ in their endpoint layout:
+//   - v2 lists the objects of a section, such as the binds of a frontend,
+//     under a flat endpoint filtered by query parameters, while v3 nests them
+//     under their parent, e.g. /configuration/frontends/<name>/binds
+//   - v2 wraps configuration objects in a {"_version", "data"} envelope, v3
+//     returns them as is
+//   - runtime map entries and runtime servers moved under their map and
+//     backend in v3
+
+// v2ParentParameter lists the v2 endpoints naming their parent with a
+// dedicated query parameter rather than parent_type and parent_name.
+var v2ParentParameter = map[string]string{
+	"servers":                 "backend",
+	"backend_switching_rules": "frontend",
+}
+
+// APIVersion returns the version of the Data Plane API the client talks to.
+func (c *Client) APIVersion() string {
+	return c.apiVersion
+}
+
+// SetAPIVersion makes the client use the endpoint layout of version.
+func (c *Client) SetAPIVersion(version string) {
+	for _, node := range c.nodes {
+		node.SetAPIVersion(version)
+	}
+	c.apiVersion = version
+	c.base_url = c.endpoint + "/" + version
+}
+
+// DetectAPIVersion probes the info endpoint of every supported version,
+// newest first, and switches the client to the first one answering.
+func (c *Client) DetectAPIVersion(ctx context.Context) (string, error) {
+	if len(c.nodes) > 0 {
+		return c.detectClusterAPIVersion(ctx)
+	}
+
+	for _, version := range []string{APIVersionV3, APIVersionV2} {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint+"/"+version+"/info", nil)
+		if err != nil {
+			return "", err
+		}
+
+		err = c.sendRequest(req, nil)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		c.SetAPIVersion(version)
+		return version, nil
+	}
+
+	return "", fmt.Errorf("no supported Dataplane API version found at %s, expected one of %s or %s", c.endpoint, APIVersionV3, APIVersionV2)
+}
+
+// transactionQuery returns the query parameters of a change made inside
+// transactionId, if any.
+func transactionQuery(transactionId string) url.Values {
+	query := url.Values{}
+	if transactionId != "" {
+		query.Set("transaction_id", transactionId)
+	}
+	return query
+}
+
+// sectionURL returns the url of the objects of endpoint belonging to the
+// parentType section parentName, or of the object id among them if id isn't
+// empty.
+func (c *Client) sectionURL(parentType string, parentName string, endpoint string, id string, query url.Values) string {
+	path := "/services/haproxy/configuration/"
+	if c.apiVersion == APIVersionV3 {
+		path += parentType + "s/" + escapePathSegment(parentName) + "/" + endpoint
+	} else {
+		path += endpoint
+		if parameter, ok := v2ParentParameter[endpoint]; ok {
+			query.Set(parameter, parentName)
+		} else {
+			query.Set("parent_type", parentType)
+			query.Set("parent_name", parentName)
+		}
+	}
+	if id != "" {
+		path += "/" + escapePathSegment(id)
+	}
+
+	return c.base_url + path + encodeQuery(query)
+}
+
+// sectionIndexURL is sectionURL for objects addressed by their index.
+func (c *Client) sectionIndexURL(parentType string, parentName string, endpoint string, index int, query url.Values) string {
+	return c.sectionURL(parentType, parentName, endpoint, strconv.Itoa(index), query)
+}
+
+func (c *Client) runtimeServerURL(backendName string, serverName string) string {
+	if c.apiVersion == APIVersionV3 {
+		return c.base_url + "/services/haproxy/runtime/backends/" + escapePathSegment(backendName) + "/servers/" + escapePathSegment(serverName)
+	}
+	return c.base_url + "/services/haproxy/runtime/servers/" + escapePathSegment(serverName) + encodeQuery(url.Values{"backend": {backendName}})
+}
+
+// mapEntriesURL returns the url of the runtime entries of a map, or of the
+// entry key if key isn't empty.
+func (c *Client) mapEntriesURL(mapName string, key string, query url.Values) string {
+	if c.apiVersion == APIVersionV3 {
+		path := "/services/haproxy/runtime/maps/" + escapePathSegment(mapName) + "/entries"
+		if key != "" {
+			path += "/" + escapePathSegment(key)
+		}
+		return c.base_url + path + encodeQuery(query)
+	}
+
+	query.Set("map", mapName)
+	return c.base_url + "/services/haproxy/runtime/maps_entries/" + escapePathSegment(key) + encodeQuery(query)
+}
+
+func encodeQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+// sendConfigurationRequest is sendRequest for endpoints returning
+// configuration objects, unwrapping the v2 envelope into v.
+func (c *Client) sendConfigurationRequest(req *http.Request, v interface{}) error {
+	if c.apiVersion == APIVersionV3 {
+		return c.sendRequest(req, v)
+	}
+
+	envelope := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := c.sendRequest(req, &envelope); err != nil {
+		return err
+	}
+	if len(envelope.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, v)
+}
@@ -0,0 +1,119 @@
+package haproxy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeVersionedAPI serves the same ACLs through the v2 and v3 layouts, and
+// only answers the info endpoint of the versions it supports.
+type fakeVersionedAPI struct {
+	versions []string
+	paths    []string
+}
+
+func (f *fakeVersionedAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.paths = append(f.paths, r.URL.RequestURI())
+	w.Header().Set("Content-Type", "application/json")
+
+	for _, version := range f.versions {
+		if r.URL.Path == "/"+version+"/info" {
+			w.Write([]byte(`{"api": {"version": "` + version + `"}}`))
+			return
+		}
+	}
+
+	switch r.URL.RequestURI() {
+	case "/v2/services/haproxy/configuration/acls?parent_name=http&parent_type=frontend":
+		w.Write([]byte(`{"_version": 3, "data": [{"index": 0, "acl_name": "is_api", "criterion": "path_beg", "value": "/api"}]}`))
+	case "/v3/services/haproxy/configuration/frontends/http/acls":
+		w.Write([]byte(`[{"index": 0, "acl_name": "is_api", "criterion": "path_beg", "value": "/api"}]`))
+	case "/v3/services/haproxy/configuration/version":
+		w.Write([]byte(`7`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": 404, "message": "not found"}`))
+	}
+}
+
+func TestDetectAPIVersion(t *testing.T) {
+	for _, expected := range []string{APIVersionV3, APIVersionV2} {
+		api := &fakeVersionedAPI{versions: []string{expected}}
+		client := newTestClient(t, api)
+
+		version, err := client.DetectAPIVersion(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if version != expected || client.APIVersion() != expected {
+			t.Fatalf("expected %s to be detected, got %s", expected, version)
+		}
+	}
+}
+
+func TestDetectAPIVersionWithoutSupportedVersion(t *testing.T) {
+	client := newTestClient(t, &fakeVersionedAPI{})
+
+	if _, err := client.DetectAPIVersion(context.Background()); err == nil {
+		t.Fatal("expected an error when no version answers")
+	}
+}
+
+func TestEndpointLayouts(t *testing.T) {
+	for _, version := range []string{APIVersionV2, APIVersionV3} {
+		api := &fakeVersionedAPI{}
+		client := newTestClient(t, api)
+		client.SetAPIVersion(version)
+
+		acls, err := client.GetAcls(context.Background(), "", "frontend", "http")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s (requests: %v)", version, err, api.paths)
+		}
+		if len(acls) != 1 || acls[0].AclName != "is_api" {
+			t.Fatalf("%s: unexpected acls %+v", version, acls)
+		}
+	}
+}
+
+func TestLayoutURLs(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+	base := strings.TrimSuffix(client.base_url, "/v2")
+
+	cases := []struct {
+		version  string
+		url      func() string
+		expected string
+	}{
+		{APIVersionV2, func() string { return client.sectionURL("backend", "api", "servers", "s1", transactionQuery("tx")) }, "/v2/services/haproxy/configuration/servers/s1?backend=api&transaction_id=tx"},
+		{APIVersionV3, func() string { return client.sectionURL("backend", "api", "servers", "s1", transactionQuery("tx")) }, "/v3/services/haproxy/configuration/backends/api/servers/s1?transaction_id=tx"},
+		{APIVersionV2, func() string {
+			return client.sectionIndexURL("frontend", "http", "backend_switching_rules", 2, transactionQuery(""))
+		}, "/v2/services/haproxy/configuration/backend_switching_rules/2?frontend=http"},
+		{APIVersionV2, func() string { return client.runtimeServerURL("api", "s1") }, "/v2/services/haproxy/runtime/servers/s1?backend=api"},
+		{APIVersionV3, func() string { return client.runtimeServerURL("api", "s1") }, "/v3/services/haproxy/runtime/backends/api/servers/s1"},
+		{APIVersionV2, func() string { return client.mapEntriesURL("hosts", "", transactionQuery("")) }, "/v2/services/haproxy/runtime/maps_entries/?map=hosts"},
+		{APIVersionV3, func() string { return client.mapEntriesURL("hosts", "a.com", transactionQuery("")) }, "/v3/services/haproxy/runtime/maps/hosts/entries/a.com"},
+	}
+
+	for _, c := range cases {
+		client.SetAPIVersion(c.version)
+		if got := strings.TrimPrefix(c.url(), base); got != c.expected {
+			t.Errorf("%s: got %s, expected %s", c.version, got, c.expected)
+		}
+	}
+}
+
+func TestGetConfigurationVersionV3(t *testing.T) {
+	client := newTestClient(t, &fakeVersionedAPI{})
+	client.SetAPIVersion(APIVersionV3)
+
+	configuration, err := client.GetConfiguration(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if configuration.Version != 7 {
+		t.Fatalf("expected version 7, got %d", configuration.Version)
+	}
+}
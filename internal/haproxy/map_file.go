@@ -0,0 +1,184 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// GetMapFiles returns the map files of the Data Plane API storage.
+func (c *Client) GetMapFiles(ctx context.Context) ([]models.MapFile, error) {
+	url := c.base_url + "/services/haproxy/storage/maps"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.MapFile{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetMapFile returns the map file name of the storage, or ErrNotFound.
+func (c *Client) GetMapFile(ctx context.Context, name string) (*models.MapFile, error) {
+	mapFiles, err := c.GetMapFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mapFile := range mapFiles {
+		if mapFile.StorageName == name {
+			return &mapFile, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// GetMapFileContent returns the content of the map file name of the storage.
+func (c *Client) GetMapFileContent(ctx context.Context, name string) (string, error) {
+	url := c.base_url + "/services/haproxy/storage/maps/" + escapePathSegment(name)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", "application/octet-stream")
+
+	res := []byte{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// CreateMapFile uploads a new map file name to the storage.
+func (c *Client) CreateMapFile(ctx context.Context, name string, content string) (*models.MapFile, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file_upload", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write([]byte(content)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	url := c.base_url + "/services/haproxy/storage/maps"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	res := models.MapFile{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ReplaceMapFile replaces the content of the map file name of the storage.
+func (c *Client) ReplaceMapFile(ctx context.Context, name string, content string) error {
+	url := c.base_url + "/services/haproxy/storage/maps/" + escapePathSegment(name)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, strings.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain")
+
+	return c.sendRequest(req, nil)
+}
+
+func (c *Client) DeleteMapFile(ctx context.Context, name string) error {
+	url := c.base_url + "/services/haproxy/storage/maps/" + escapePathSegment(name)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.sendRequest(req, nil)
+}
+
+// GetRuntimeMaps returns the maps loaded by HAProxy.
+func (c *Client) GetRuntimeMaps(ctx context.Context) ([]models.Map, error) {
+	url := c.base_url + "/services/haproxy/runtime/maps"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.Map{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetRuntimeMap returns the map mapName loaded by HAProxy. A map file is
+// only loaded once referenced by the configuration.
+func (c *Client) GetRuntimeMap(ctx context.Context, mapName string) (*models.Map, error) {
+	url := c.base_url + "/services/haproxy/runtime/maps/" + escapePathSegment(mapName)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.Map{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// FormatMapFile renders entries as the content of a map file, one
+// "key value" line per entry sorted by key.
+func FormatMapFile(entries map[string]string) string {
+	keys := []string{}
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var content strings.Builder
+	for _, key := range keys {
+		content.WriteString(key + " " + entries[key] + "\n")
+	}
+	return content.String()
+}
+
+// ParseMapFile returns the entries of the content of a map file, skipping
+// blank lines and comments. The key ends at the first blank, the value is
+// the rest of the line.
+func ParseMapFile(content string) map[string]string {
+	entries := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			key, value = line[:i], strings.TrimSpace(line[i:])
+		}
+		entries[key] = value
+	}
+	return entries
+}
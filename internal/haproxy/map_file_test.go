@@ -0,0 +1,85 @@
+package haproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseMapFile(t *testing.T) {
+	content := "# rate limits\n/api 200\n\n/metrics\t50\n/admin   10 per minute\n/empty\n"
+
+	expected := map[string]string{"/api": "200", "/metrics": "50", "/admin": "10 per minute", "/empty": ""}
+	if entries := ParseMapFile(content); !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("expected %v, got %v", expected, entries)
+	}
+}
+
+func TestFormatMapFile(t *testing.T) {
+	entries := map[string]string{"/metrics": "50", "/api": "200"}
+
+	content := FormatMapFile(entries)
+	if content != "/api 200\n/metrics 50\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+	if !reflect.DeepEqual(ParseMapFile(content), entries) {
+		t.Fatalf("expected the content to parse back to %v", entries)
+	}
+}
+
+// fakeStorageAPI stores the map files uploaded through the v2 layout.
+type fakeStorageAPI struct {
+	files map[string]string
+}
+
+func (f *fakeStorageAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/v2/services/haproxy/storage/maps":
+		file, header, err := r.FormFile("file_upload")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		f.files[header.Filename] = string(content)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"storage_name": "` + header.Filename + `", "file": "/etc/haproxy/maps/` + header.Filename + `"}`))
+	case r.Method == "GET" && r.URL.Path == "/v2/services/haproxy/storage/maps":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"storage_name": "ratelimit.map", "file": "/etc/haproxy/maps/ratelimit.map"}]`))
+	case r.Method == "GET" && r.URL.Path == "/v2/services/haproxy/storage/maps/ratelimit.map":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte(f.files["ratelimit.map"]))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestMapFileStorage(t *testing.T) {
+	api := &fakeStorageAPI{files: map[string]string{}}
+	client := newTestClient(t, api)
+	ctx := context.Background()
+
+	created, err := client.CreateMapFile(ctx, "ratelimit.map", "/api 200\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created.File != "/etc/haproxy/maps/ratelimit.map" {
+		t.Fatalf("unexpected path %s", created.File)
+	}
+
+	content, err := client.GetMapFileContent(ctx, "ratelimit.map")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if content != "/api 200\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+
+	if _, err := client.GetMapFile(ctx, "missing.map"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
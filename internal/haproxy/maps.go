@@ -2,16 +2,57 @@ package haproxy
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
 )
 
-func (c *Client) GetMapEntrie(entrieName string, mapName string) (*models.MapEntrie, error) {
-	url := c.base_url + "/services/haproxy/runtime/maps_entries/" + encodeUrl(entrieName) + "?map=" + mapName
-	req, err := http.NewRequest("GET", url, nil)
+// InvalidMapEntrieError is returned for map entries HAProxy can't store. The
+// runtime API splits its arguments on blanks and map files hold one entry
+// per line, so a key containing a blank, or a value containing a line break,
+// would be stored truncated or under another key.
+type InvalidMapEntrieError struct {
+	Key    string
+	Reason string
+}
+
+func (e *InvalidMapEntrieError) Error() string {
+	return "Cannot insert " + e.Key + ". " + e.Reason
+}
+
+// ValidateMapKey returns an InvalidMapEntrieError if HAProxy can't store key.
+func ValidateMapKey(key string) error {
+	if key == "" {
+		return &InvalidMapEntrieError{Key: key, Reason: "Empty key is not allowed."}
+	}
+	if strings.IndexFunc(key, unicode.IsSpace) >= 0 {
+		return &InvalidMapEntrieError{Key: key, Reason: "Space is not allowed."}
+	}
+	return nil
+}
+
+// ValidateMapEntrie returns an InvalidMapEntrieError if HAProxy can't store
+// entrie.
+func ValidateMapEntrie(entrie models.MapEntrie) error {
+	if err := ValidateMapKey(entrie.Key); err != nil {
+		return err
+	}
+	if strings.ContainsAny(entrie.Value, "\r\n") {
+		return &InvalidMapEntrieError{Key: entrie.Key, Reason: "Line break is not allowed in the value."}
+	}
+	return nil
+}
+
+func (c *Client) GetMapEntrie(ctx context.Context, entrieName string, mapName string) (*models.MapEntrie, error) {
+	url := c.mapEntriesURL(mapName, entrieName, url.Values{})
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -24,10 +65,30 @@ func (c *Client) GetMapEntrie(entrieName string, mapName string) (*models.MapEnt
 	return &res, nil
 }
 
-func (c *Client) CreateMapEntrie(entrie *models.MapEntrie, mapName string, forceSync bool) (*models.MapEntrie, error) {
-	url := c.base_url + "/services/haproxy/runtime/maps_entries/?map=" + mapName + "&force_sync=" + strconv.FormatBool(forceSync)
+// GetMapEntries returns every entry of the runtime map mapName.
+func (c *Client) GetMapEntries(ctx context.Context, mapName string) ([]models.MapEntrie, error) {
+	url := c.mapEntriesURL(mapName, "", url.Values{})
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.MapEntrie{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) CreateMapEntrie(ctx context.Context, entrie *models.MapEntrie, mapName string, forceSync bool) (*models.MapEntrie, error) {
+	if err := ValidateMapEntrie(*entrie); err != nil {
+		return nil, err
+	}
+
+	url := c.mapEntriesURL(mapName, "", url.Values{"force_sync": {strconv.FormatBool(forceSync)}})
 	bodyStr, _ := json.Marshal(entrie)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyStr))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
 	if err != nil {
 		return nil, err
 	}
@@ -42,13 +103,17 @@ func (c *Client) CreateMapEntrie(entrie *models.MapEntrie, mapName string, force
 	return &res, nil
 }
 
-func (c *Client) UpdateMapEntrie(entrie *models.MapEntrie, mapName string, forceSync bool) (*models.MapEntrie, error) {
-	url := c.base_url + "/services/haproxy/runtime/maps_entries/" + encodeUrl(entrie.Key) + "?map=" + mapName + "&force_sync=" + strconv.FormatBool(forceSync)
+func (c *Client) UpdateMapEntrie(ctx context.Context, entrie *models.MapEntrie, mapName string, forceSync bool) (*models.MapEntrie, error) {
+	if err := ValidateMapEntrie(*entrie); err != nil {
+		return nil, err
+	}
+
+	url := c.mapEntriesURL(mapName, entrie.Key, url.Values{"force_sync": {strconv.FormatBool(forceSync)}})
 	entrieValue := &models.MapEntrie{
 		Value: entrie.Value,
 	}
 	bodyStr, _ := json.Marshal(entrieValue)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(bodyStr))
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
 	if err != nil {
 		return nil, err
 	}
@@ -63,9 +128,9 @@ func (c *Client) UpdateMapEntrie(entrie *models.MapEntrie, mapName string, force
 	return &res, nil
 }
 
-func (c *Client) DeleteMapEntrie(entrieName string, mapName string, forceSync bool) error {
-	url := c.base_url + "/services/haproxy/runtime/maps_entries/" + encodeUrl(entrieName) + "?map=" + mapName + "&force_sync=" + strconv.FormatBool(forceSync)
-	req, err := http.NewRequest("DELETE", url, nil)
+func (c *Client) DeleteMapEntrie(ctx context.Context, entrieName string, mapName string, forceSync bool) error {
+	url := c.mapEntriesURL(mapName, entrieName, url.Values{"force_sync": {strconv.FormatBool(forceSync)}})
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -78,3 +143,138 @@ func (c *Client) DeleteMapEntrie(entrieName string, mapName string, forceSync bo
 	return nil
 
 }
+
+// MapSyncOptions tunes how SyncMapEntries changes a runtime map.
+type MapSyncOptions struct {
+	// Stale are keys deleted when not in entries, typically the entries
+	// managed previously.
+	Stale []string
+	// RemoveUnmanaged deletes every key not in entries.
+	RemoveUnmanaged bool
+	// ForceSync writes the map file once every change is made.
+	ForceSync bool
+	// BulkChunkSize, when positive, adds the new entries with the runtime
+	// "add map" payload, at most BulkChunkSize entries per request, instead
+	// of one request per entry.
+	BulkChunkSize int
+}
+
+// SyncMapEntries makes the runtime map mapName hold entries, adding and
+// setting only the entries that differ and deleting the ones options ask
+// for. Only the last change is sent with force_sync, so the map file is
+// written once.
+func (c *Client) SyncMapEntries(ctx context.Context, mapName string, entries map[string]string, options MapSyncOptions) error {
+	// every entry is checked first, so an invalid one doesn't leave the map
+	// half synced
+	for key, value := range entries {
+		if err := ValidateMapEntrie(models.MapEntrie{Key: key, Value: value}); err != nil {
+			return err
+		}
+	}
+
+	current, err := c.GetMapEntries(ctx, mapName)
+	if err != nil {
+		return err
+	}
+
+	currentValues := map[string]string{}
+	for _, entrie := range current {
+		currentValues[entrie.Key] = entrie.Value
+	}
+
+	toDelete := []string{}
+	if options.RemoveUnmanaged {
+		for key := range currentValues {
+			if _, ok := entries[key]; !ok {
+				toDelete = append(toDelete, key)
+			}
+		}
+	} else {
+		for _, key := range options.Stale {
+			_, declared := entries[key]
+			if _, ok := currentValues[key]; ok && !declared {
+				toDelete = append(toDelete, key)
+			}
+		}
+	}
+	sort.Strings(toDelete)
+
+	toCreate, toUpdate := []string{}, []string{}
+	for key, value := range entries {
+		currentValue, ok := currentValues[key]
+		switch {
+		case !ok:
+			toCreate = append(toCreate, key)
+		case currentValue != value:
+			toUpdate = append(toUpdate, key)
+		}
+	}
+	sort.Strings(toCreate)
+	sort.Strings(toUpdate)
+
+	chunks := [][]string{}
+	if options.BulkChunkSize > 0 {
+		for len(toCreate) > 0 {
+			size := options.BulkChunkSize
+			if size > len(toCreate) {
+				size = len(toCreate)
+			}
+			chunks = append(chunks, toCreate[:size])
+			toCreate = toCreate[size:]
+		}
+	}
+
+	remaining := len(toDelete) + len(toUpdate) + len(toCreate) + len(chunks)
+	last := func() bool {
+		remaining--
+		return options.ForceSync && remaining == 0
+	}
+
+	for _, key := range toDelete {
+		if err := c.DeleteMapEntrie(ctx, key, mapName, last()); err != nil {
+			return err
+		}
+	}
+	for _, key := range toUpdate {
+		if _, err := c.UpdateMapEntrie(ctx, &models.MapEntrie{Key: key, Value: entries[key]}, mapName, last()); err != nil {
+			return err
+		}
+	}
+	for _, key := range toCreate {
+		if _, err := c.CreateMapEntrie(ctx, &models.MapEntrie{Key: key, Value: entries[key]}, mapName, last()); err != nil {
+			return err
+		}
+	}
+	for _, chunk := range chunks {
+		payload := []models.MapEntrie{}
+		for _, key := range chunk {
+			payload = append(payload, models.MapEntrie{Key: key, Value: entries[key]})
+		}
+		if err := c.AddMapPayload(ctx, mapName, payload, last()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddMapPayload adds entries to the runtime map mapName in a single request,
+// with the runtime "add map" payload.
+func (c *Client) AddMapPayload(ctx context.Context, mapName string, entries []models.MapEntrie, forceSync bool) error {
+	for _, entrie := range entries {
+		if err := ValidateMapEntrie(entrie); err != nil {
+			return err
+		}
+	}
+
+	url := c.base_url + "/services/haproxy/runtime/maps/" + escapePathSegment(mapName) + encodeQuery(url.Values{"force_sync": {strconv.FormatBool(forceSync)}})
+	bodyStr, _ := json.Marshal(entries)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.sendRequest(req, nil)
+}
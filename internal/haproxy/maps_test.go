@@ -0,0 +1,238 @@
+package haproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// fakeRuntimeMapAPI is a runtime map served through the v2 layout,
+// recording the changes made to it.
+type fakeRuntimeMapAPI struct {
+	mu      sync.Mutex
+	entries map[string]string
+	changes []string
+}
+
+func (f *fakeRuntimeMapAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	key := strings.TrimPrefix(r.URL.Path, "/v2/services/haproxy/runtime/maps_entries/")
+	if r.Method != "GET" {
+		f.changes = append(f.changes, r.Method+" "+key+" force_sync="+r.URL.Query().Get("force_sync"))
+	}
+
+	switch {
+	case r.URL.Path == "/v2/services/haproxy/runtime/maps/test":
+		payload := []models.MapEntrie{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		for _, entrie := range payload {
+			f.entries[entrie.Key] = entrie.Value
+		}
+		f.changes[len(f.changes)-1] += fmt.Sprintf(" entries=%d", len(payload))
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == "GET":
+		res := []models.MapEntrie{}
+		for key, value := range f.entries {
+			res = append(res, models.MapEntrie{Key: key, Value: value})
+		}
+		json.NewEncoder(w).Encode(res)
+	case r.Method == "POST":
+		entrie := models.MapEntrie{}
+		json.NewDecoder(r.Body).Decode(&entrie)
+		f.entries[entrie.Key] = entrie.Value
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(entrie)
+	case r.Method == "PUT":
+		entrie := models.MapEntrie{}
+		json.NewDecoder(r.Body).Decode(&entrie)
+		f.entries[key] = entrie.Value
+		json.NewEncoder(w).Encode(entrie)
+	case r.Method == "DELETE":
+		delete(f.entries, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestSyncMapEntries(t *testing.T) {
+	tests := []struct {
+		name            string
+		stale           []string
+		removeUnmanaged bool
+		expected        map[string]string
+		changes         []string
+	}{
+		{
+			name:     "keeps unmanaged entries",
+			stale:    []string{"old"},
+			expected: map[string]string{"same": "1", "changed": "3", "new": "4", "unmanaged": "5"},
+			changes: []string{
+				"DELETE old force_sync=false",
+				"PUT changed force_sync=false",
+				"POST  force_sync=true",
+			},
+		},
+		{
+			name:            "removes unmanaged entries",
+			removeUnmanaged: true,
+			expected:        map[string]string{"same": "1", "changed": "3", "new": "4"},
+			changes: []string{
+				"DELETE old force_sync=false",
+				"DELETE unmanaged force_sync=false",
+				"PUT changed force_sync=false",
+				"POST  force_sync=true",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &fakeRuntimeMapAPI{entries: map[string]string{"same": "1", "changed": "2", "old": "0", "unmanaged": "5"}}
+			client := newTestClient(t, api)
+
+			entries := map[string]string{"same": "1", "changed": "3", "new": "4"}
+			if err := client.SyncMapEntries(context.Background(), "test", entries, MapSyncOptions{
+				Stale:           test.stale,
+				RemoveUnmanaged: test.removeUnmanaged,
+				ForceSync:       true,
+			}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(api.entries, test.expected) {
+				t.Fatalf("expected entries %v, got %v", test.expected, api.entries)
+			}
+			if !reflect.DeepEqual(api.changes, test.changes) {
+				t.Fatalf("expected changes %v, got %v", test.changes, api.changes)
+			}
+		})
+	}
+}
+
+func TestSyncMapEntriesInBulk(t *testing.T) {
+	api := &fakeRuntimeMapAPI{entries: map[string]string{"same": "1", "changed": "2"}}
+	client := newTestClient(t, api)
+
+	entries := map[string]string{"same": "1", "changed": "3"}
+	for i := 0; i < 2500; i++ {
+		entries[fmt.Sprintf("/key/%d", i)] = "value"
+	}
+
+	err := client.SyncMapEntries(context.Background(), "test", entries, MapSyncOptions{ForceSync: true, BulkChunkSize: 1000})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(api.entries, entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), len(api.entries))
+	}
+	expected := []string{
+		"PUT changed force_sync=false",
+		"PUT /v2/services/haproxy/runtime/maps/test force_sync=false entries=1000",
+		"PUT /v2/services/haproxy/runtime/maps/test force_sync=false entries=1000",
+		"PUT /v2/services/haproxy/runtime/maps/test force_sync=true entries=500",
+	}
+	if !reflect.DeepEqual(api.changes, expected) {
+		t.Fatalf("expected changes %v, got %v", expected, api.changes)
+	}
+}
+
+// fakeMapKeyAPI records the map name and key a map entry request reaches
+// the Data Plane API with, once decoded.
+type fakeMapKeyAPI struct {
+	mapName string
+	key     string
+}
+
+func (f *fakeMapKeyAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+	f.key, _ = url.PathUnescape(segments[len(segments)-1])
+	if strings.HasPrefix(r.URL.Path, "/v3/") {
+		f.mapName, _ = url.PathUnescape(segments[len(segments)-3])
+	} else {
+		f.mapName = r.URL.Query().Get("map")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.MapEntrie{Key: f.key, Value: "1"})
+}
+
+func TestMapEntrieKeyEncoding(t *testing.T) {
+	keys := []string{
+		"https://preprod.example.com|identity",
+		"/test1/test2",
+		"/search?q=a&b=c",
+		"100%",
+		"a+b",
+		"#fragment",
+		"héllo/wörld/日本",
+	}
+	mapNames := []string{"test", "/etc/haproxy/maps/test.map"}
+
+	for _, version := range []string{APIVersionV2, APIVersionV3} {
+		api := &fakeMapKeyAPI{}
+		client := newTestClient(t, api)
+		client.SetAPIVersion(version)
+
+		for _, mapName := range mapNames {
+			for _, key := range keys {
+				if _, err := client.GetMapEntrie(context.Background(), key, mapName); err != nil {
+					t.Fatalf("unexpected error for %s: %s", key, err)
+				}
+				if api.key != key || api.mapName != mapName {
+					t.Fatalf("%s: expected key %q of map %q, got key %q of map %q", version, key, mapName, api.key, api.mapName)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateMapEntrie(t *testing.T) {
+	tests := []struct {
+		entrie models.MapEntrie
+		err    string
+	}{
+		{entrie: models.MapEntrie{Key: "https://example.com|identity?q=%20", Value: "enable"}},
+		{entrie: models.MapEntrie{Key: "日本", Value: "value with spaces"}},
+		{entrie: models.MapEntrie{Key: "test with bad key"}, err: "Cannot insert test with bad key. Space is not allowed."},
+		{entrie: models.MapEntrie{Key: "tab\tkey"}, err: "Cannot insert tab\tkey. Space is not allowed."},
+		{entrie: models.MapEntrie{Key: ""}, err: "Cannot insert . Empty key is not allowed."},
+		{entrie: models.MapEntrie{Key: "key", Value: "two\nlines"}, err: "Cannot insert key. Line break is not allowed in the value."},
+	}
+
+	for _, test := range tests {
+		err := ValidateMapEntrie(test.entrie)
+		if test.err == "" && err != nil {
+			t.Fatalf("unexpected error for %q: %s", test.entrie.Key, err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Fatalf("expected %q for %q, got %v", test.err, test.entrie.Key, err)
+		}
+	}
+}
+
+func TestCreateMapEntrieRejectsInvalidKey(t *testing.T) {
+	api := &fakeRuntimeMapAPI{entries: map[string]string{}}
+	client := newTestClient(t, api)
+
+	_, err := client.CreateMapEntrie(context.Background(), &models.MapEntrie{Key: "bad key", Value: "1"}, "test", true)
+
+	var invalid *InvalidMapEntrieError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected an InvalidMapEntrieError, got %v", err)
+	}
+	if len(api.changes) != 0 {
+		t.Fatalf("expected no request to be sent, got %v", api.changes)
+	}
+}
@@ -0,0 +1,8 @@
+package models
+
+type Acl struct {
+	AclName   string `json:"acl_name"`
+	Criterion string `json:"criterion"`
+	Index     *int   `json:"index"`
+	Value     string `json:"value,omitempty"`
+}
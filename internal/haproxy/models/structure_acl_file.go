@@ -0,0 +1,12 @@
+package models
+
+type AclFile struct {
+	Description string `json:"description,omitempty"`
+	Id          string `json:"id,omitempty"`
+	StorageName string `json:"storage_name,omitempty"`
+}
+
+type AclFileEntry struct {
+	Id    string `json:"id,omitempty"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,78 @@
+package models
+
+type Backend struct {
+	AdvCheck             string         `json:"adv_check,omitempty"`
+	Balance              *Balance       `json:"balance,omitempty"`
+	BindProcess          string         `json:"bind_process,omitempty"`
+	CheckTimeout         int            `json:"check_timeout,omitempty"`
+	ConnectTimeout       int            `json:"connect_timeout,omitempty"`
+	Cookie               *Cookie        `json:"cookie,omitempty"`
+	Forwardfor           *Forwardfor    `json:"forwardfor,omitempty"`
+	HttpCheck            *HttpCheck     `json:"http-check,omitempty"`
+	HttpchkParams        *HttpchkParams `json:"httpchk_params,omitempty"`
+	HttpConnectionMode   string         `json:"http_connection_mode,omitempty"`
+	HttpKeepAliveTimeout int            `json:"http_keep_alive_timeout,omitempty"`
+	HttpRequestTimeout   int            `json:"http_request_timeout,omitempty"`
+	LogTag               string         `json:"log_tag,omitempty"`
+	Mode                 string         `json:"mode,omitempty"`
+	Name                 string         `json:"name"`
+	QueueTimeout         int            `json:"queue_timeout,omitempty"`
+	Retries              int            `json:"retries,omitempty"`
+	ServerTimeout        int            `json:"server_timeout,omitempty"`
+	StickTable           *StickTable    `json:"stick_table,omitempty"`
+	TunnelTimeout        int            `json:"tunnel_timeout,omitempty"`
+}
+
+type Balance struct {
+	Algorithm        string `json:"algorithm"`
+	HashExpression   string `json:"hash_expression,omitempty"`
+	HdrName          string `json:"hdr_name,omitempty"`
+	HdrUseDomainOnly bool   `json:"hdr_use_domain_only,omitempty"`
+	RdpCookieName    string `json:"rdp_cookie_name,omitempty"`
+	UriDepth         int    `json:"uri_depth,omitempty"`
+	UriLen           int    `json:"uri_len,omitempty"`
+	UriWhole         bool   `json:"uri_whole,omitempty"`
+	UrlParam         string `json:"url_param,omitempty"`
+}
+
+type Cookie struct {
+	Domains  []CookieDomain `json:"domain,omitempty"`
+	Dynamic  bool           `json:"dynamic,omitempty"`
+	Httponly bool           `json:"httponly,omitempty"`
+	Indirect bool           `json:"indirect,omitempty"`
+	Maxidle  int            `json:"maxidle,omitempty"`
+	Maxlife  int            `json:"maxlife,omitempty"`
+	Name     string         `json:"name"`
+	Nocache  bool           `json:"nocache,omitempty"`
+	Postonly bool           `json:"postonly,omitempty"`
+	Preserve bool           `json:"preserve,omitempty"`
+	Secure   bool           `json:"secure,omitempty"`
+	Type     string         `json:"type,omitempty"`
+}
+
+type CookieDomain struct {
+	Value string `json:"value"`
+}
+
+type HttpCheck struct {
+	ExclamationMark bool   `json:"exclamation_mark,omitempty"`
+	Match           string `json:"match,omitempty"`
+	Pattern         string `json:"pattern,omitempty"`
+	Type            string `json:"type"`
+}
+
+type HttpchkParams struct {
+	Method  string `json:"method,omitempty"`
+	Uri     string `json:"uri,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type StickTable struct {
+	Expire  int    `json:"expire,omitempty"`
+	Keylen  int    `json:"keylen,omitempty"`
+	Nopurge bool   `json:"nopurge,omitempty"`
+	Peers   string `json:"peers,omitempty"`
+	Size    int    `json:"size,omitempty"`
+	Store   string `json:"store,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type BackendSwitchingRule struct {
+	Cond     string `json:"cond,omitempty"`
+	CondTest string `json:"cond_test,omitempty"`
+	Index    *int   `json:"index"`
+	Name     string `json:"name"`
+}
@@ -0,0 +1,19 @@
+package models
+
+type Bind struct {
+	AcceptProxy    bool   `json:"accept_proxy,omitempty"`
+	Address        string `json:"address,omitempty"`
+	Alpn           string `json:"alpn,omitempty"`
+	Ciphers        string `json:"ciphers,omitempty"`
+	CrtList        string `json:"crt_list,omitempty"`
+	Group          string `json:"group,omitempty"`
+	Mode           string `json:"mode,omitempty"`
+	Name           string `json:"name"`
+	Port           *int   `json:"port,omitempty"`
+	PortRangeEnd   *int   `json:"port-range-end,omitempty"`
+	Ssl            bool   `json:"ssl,omitempty"`
+	SslCertificate string `json:"ssl_certificate,omitempty"`
+	Transparent    bool   `json:"transparent,omitempty"`
+	User           string `json:"user,omitempty"`
+	V4v6           bool   `json:"v4v6,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// HttpRule holds both http-request and http-response rules, the Data Plane
+// API models of the two only differ by the actions they accept.
+type HttpRule struct {
+	Cond                string `json:"cond,omitempty"`
+	CondTest            string `json:"cond_test,omitempty"`
+	DenyStatus          int    `json:"deny_status,omitempty"`
+	HdrFormat           string `json:"hdr_format,omitempty"`
+	HdrMatch            string `json:"hdr_match,omitempty"`
+	HdrName             string `json:"hdr_name,omitempty"`
+	Index               *int   `json:"index"`
+	LuaAction           string `json:"lua_action,omitempty"`
+	LuaParams           string `json:"lua_params,omitempty"`
+	MapFile             string `json:"map_file,omitempty"`
+	MapKeyfmt           string `json:"map_keyfmt,omitempty"`
+	MapValuefmt         string `json:"map_valuefmt,omitempty"`
+	RedirCode           int    `json:"redir_code,omitempty"`
+	RedirOption         string `json:"redir_option,omitempty"`
+	RedirType           string `json:"redir_type,omitempty"`
+	RedirValue          string `json:"redir_value,omitempty"`
+	ReturnContent       string `json:"return_content,omitempty"`
+	ReturnContentFormat string `json:"return_content_format,omitempty"`
+	ReturnContentType   string `json:"return_content_type,omitempty"`
+	ReturnStatusCode    int    `json:"return_status_code,omitempty"`
+	ServiceName         string `json:"service_name,omitempty"`
+	Type                string `json:"type"`
+	VarExpr             string `json:"var_expr,omitempty"`
+	VarName             string `json:"var_name,omitempty"`
+	VarScope            string `json:"var_scope,omitempty"`
+}
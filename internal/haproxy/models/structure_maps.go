@@ -5,3 +5,17 @@ type MapEntrie struct {
 	Key   string `json:"key,omitempty"`
 	Value string `json:"value"`
 }
+
+type Map struct {
+	Description string `json:"description,omitempty"`
+	File        string `json:"file,omitempty"`
+	Id          string `json:"id,omitempty"`
+}
+
+type MapFile struct {
+	Description string `json:"description,omitempty"`
+	File        string `json:"file,omitempty"`
+	Id          string `json:"id,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	StorageName string `json:"storage_name,omitempty"`
+}
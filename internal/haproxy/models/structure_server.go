@@ -0,0 +1,29 @@
+package models
+
+type Server struct {
+	Address     string `json:"address"`
+	Backup      string `json:"backup,omitempty"`
+	Check       string `json:"check,omitempty"`
+	Fall        int    `json:"fall,omitempty"`
+	Inter       int    `json:"inter,omitempty"`
+	Maintenance string `json:"maintenance,omitempty"`
+	MaxConn     int    `json:"maxconn,omitempty"`
+	Name        string `json:"name"`
+	Port        int    `json:"port,omitempty"`
+	Rise        int    `json:"rise,omitempty"`
+	SendProxy   string `json:"send-proxy,omitempty"`
+	Sni         string `json:"sni,omitempty"`
+	Ssl         string `json:"ssl,omitempty"`
+	Verify      string `json:"verify,omitempty"`
+	Weight      *int   `json:"weight,omitempty"`
+}
+
+type RuntimeServer struct {
+	Address          string `json:"address,omitempty"`
+	AdminState       string `json:"admin_state,omitempty"`
+	Id               string `json:"id,omitempty"`
+	Name             string `json:"name,omitempty"`
+	OperationalState string `json:"operational_state,omitempty"`
+	Port             int    `json:"port,omitempty"`
+	Weight           *int   `json:"weight,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// TcpRule holds both tcp-request and tcp-response rules, the latter only
+// support the 'content' and 'inspect-delay' types.
+type TcpRule struct {
+	Action        string `json:"action,omitempty"`
+	CaptureLen    int    `json:"capture_len,omitempty"`
+	CaptureSample string `json:"capture_sample,omitempty"`
+	Cond          string `json:"cond,omitempty"`
+	CondTest      string `json:"cond_test,omitempty"`
+	Expr          string `json:"expr,omitempty"`
+	Index         *int   `json:"index"`
+	LuaAction     string `json:"lua_action,omitempty"`
+	LuaParams     string `json:"lua_params,omitempty"`
+	Timeout       int    `json:"timeout,omitempty"`
+	TrackKey      string `json:"track_key,omitempty"`
+	TrackTable    string `json:"track_table,omitempty"`
+	Type          string `json:"type"`
+	VarName       string `json:"var_name,omitempty"`
+	VarScope      string `json:"var_scope,omitempty"`
+}
@@ -0,0 +1,61 @@
+package haproxy
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/avast/retry-go"
+)
+
+const (
+	DefaultRetryAttempts = 10
+	DefaultRetryMaxWait  = 5 * time.Second
+
+	// retryDelay is the first backoff delay, doubled on every attempt up to
+	// RetryMaxWait. Up to retryJitter is added so that concurrent changes
+	// that failed together don't retry in lockstep.
+	retryDelay  = 100 * time.Millisecond
+	retryJitter = 100 * time.Millisecond
+)
+
+// IsRetryable reports whether an operation failing with err may succeed when
+// retried from scratch: version mismatches, transient API and network errors,
+// and batches aborted by a failure of another change.
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrBatchAborted) {
+		return true
+	}
+
+	if apiErr, ok := AsAPIError(err); ok {
+		return errors.Is(apiErr, ErrVersionMismatch) ||
+			errors.Is(apiErr, ErrNotAcceptable) ||
+			apiErr.StatusCode >= 500
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return false
+}
+
+// Retry runs fn until it succeeds, fails with an error that isn't retryable or
+// RetryAttempts is reached, backing off exponentially between attempts. fn
+// must re-read the configuration version on each attempt. Retrying stops as
+// soon as ctx is done.
+func (c *Client) Retry(ctx context.Context, fn func() error) error {
+	return retry.Do(
+		fn,
+		retry.Attempts(c.RetryAttempts),
+		retry.Delay(retryDelay),
+		retry.MaxJitter(retryJitter),
+		retry.MaxDelay(c.RetryMaxWait),
+		retry.DelayType(retry.CombineDelay(retry.BackOffDelay, retry.RandomDelay)),
+		retry.RetryIf(IsRetryable),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	)
+}
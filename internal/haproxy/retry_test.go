@@ -0,0 +1,158 @@
+package haproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeFlakyAPI answers GET /services/haproxy/configuration/raw with status
+// until failures attempts were made, then succeeds.
+type fakeFlakyAPI struct {
+	status   int
+	message  string
+	failures int32
+	calls    int32
+}
+
+func (f *fakeFlakyAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !strings.HasSuffix(r.URL.Path, "/configuration/raw") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if atomic.AddInt32(&f.calls, 1) <= f.failures {
+		w.WriteHeader(f.status)
+		fmt.Fprintf(w, `{"code": 1, "message": %q}`, f.message)
+		return
+	}
+	w.Write([]byte(`{"_version": 1, "data": ""}`))
+}
+
+func TestRetryRecoversFromVersionConflicts(t *testing.T) {
+	api := &fakeFlakyAPI{status: http.StatusConflict, message: "version mismatch", failures: 2}
+	client := newTestClient(t, api)
+	client.RetryMaxWait = 10 * time.Millisecond
+
+	err := client.Retry(context.Background(), func() error {
+		_, err := client.GetConfiguration(context.Background())
+		return err
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if api.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", api.calls)
+	}
+}
+
+func TestRetryStopsOnValidationErrors(t *testing.T) {
+	api := &fakeFlakyAPI{status: http.StatusBadRequest, failures: 10}
+	client := newTestClient(t, api)
+	client.RetryMaxWait = 10 * time.Millisecond
+
+	err := client.Retry(context.Background(), func() error {
+		_, err := client.GetConfiguration(context.Background())
+		return err
+	})
+
+	if !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected a bad request error, got %v", err)
+	}
+	if api.calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", api.calls)
+	}
+}
+
+func TestRetryStopsOnExistingObjects(t *testing.T) {
+	api := &fakeFlakyAPI{status: http.StatusConflict, message: "backend test already exists", failures: 10}
+	client := newTestClient(t, api)
+	client.RetryMaxWait = 10 * time.Millisecond
+
+	err := client.Retry(context.Background(), func() error {
+		_, err := client.GetConfiguration(context.Background())
+		return err
+	})
+
+	if !errors.Is(err, ErrConflict) || errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("expected an existing object conflict, got %v", err)
+	}
+	if api.calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", api.calls)
+	}
+}
+
+func TestRetryGivesUpAfterAttempts(t *testing.T) {
+	api := &fakeFlakyAPI{status: http.StatusServiceUnavailable, failures: 10}
+	client := newTestClient(t, api)
+	client.RetryAttempts = 3
+	client.RetryMaxWait = 10 * time.Millisecond
+
+	err := client.Retry(context.Background(), func() error {
+		_, err := client.GetConfiguration(context.Background())
+		return err
+	})
+
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if api.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", api.calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{&APIError{StatusCode: http.StatusConflict, Message: "version mismatch"}, true},
+		{&APIError{StatusCode: http.StatusConflict, Message: "Version in configuration file is 4, given version is 3"}, true},
+		{&APIError{StatusCode: http.StatusConflict, Message: "frontend public already exists"}, false},
+		{&APIError{StatusCode: http.StatusConflict}, false},
+		{&APIError{StatusCode: http.StatusNotAcceptable}, true},
+		{&APIError{StatusCode: http.StatusBadGateway}, true},
+		{&APIError{StatusCode: http.StatusBadRequest}, false},
+		{&APIError{StatusCode: http.StatusUnauthorized}, false},
+		{ErrNotFound, false},
+		{ErrBatchAborted, true},
+		{errors.New("invalid value"), false},
+	}
+
+	for _, c := range cases {
+		if got := IsRetryable(c.err); got != c.retryable {
+			t.Errorf("IsRetryable(%#v) = %t, expected %t", c.err, got, c.retryable)
+		}
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	api := &fakeFlakyAPI{status: http.StatusServiceUnavailable, failures: 100}
+	client := newTestClient(t, api)
+	client.RetryMaxWait = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.Retry(ctx, func() error {
+		_, err := client.GetConfiguration(ctx)
+		return err
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected retries to stop with the context, took %s", elapsed)
+	}
+	if api.calls >= int32(client.RetryAttempts) {
+		t.Fatalf("expected fewer than %d attempts, got %d", client.RetryAttempts, api.calls)
+	}
+}
@@ -0,0 +1,63 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Rule lists (http-request, http-response, tcp-request, ...) share the same
+// index based endpoints under /services/haproxy/configuration/<endpoint>.
+
+func (c *Client) getRules(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string, v interface{}) error {
+	url := c.sectionURL(parentType, parentName, endpoint, "", transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.sendConfigurationRequest(req, v)
+}
+
+func (c *Client) createRule(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string, rule interface{}) error {
+	url := c.sectionURL(parentType, parentName, endpoint, "", transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(rule)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.sendRequest(req, nil)
+}
+
+func (c *Client) deleteRule(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string, index int) error {
+	url := c.sectionIndexURL(parentType, parentName, endpoint, index, transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.sendRequest(req, nil)
+}
+
+// replaceRules deletes the existing rules, last one first so the remaining
+// indexes stay valid, then creates the new ones in order. Run inside a
+// transaction, the list is swapped atomically on commit.
+func (c *Client) replaceRules(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string, existing int, rules []interface{}) error {
+	for index := existing - 1; index >= 0; index-- {
+		if err := c.deleteRule(ctx, transactionId, endpoint, parentType, parentName, index); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range rules {
+		if err := c.createRule(ctx, transactionId, endpoint, parentType, parentName, rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package haproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func (c *Client) GetServer(ctx context.Context, backendName string, server models.Server) (*models.Server, error) {
+	url := c.sectionURL("backend", backendName, "servers", server.Name, url.Values{})
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.Server{}
+	if err := c.sendConfigurationRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) CreateServer(ctx context.Context, transactionId string, backendName string, server models.Server) (*models.Server, error) {
+	url := c.sectionURL("backend", backendName, "servers", "", transactionQuery(transactionId))
+	bodyStr, _ := json.Marshal(server)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Server{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) UpdateServer(ctx context.Context, transactionId string, backendName string, server models.Server) (*models.Server, error) {
+	url := c.sectionURL("backend", backendName, "servers", server.Name, transactionQuery(transactionId))
+	return c.replaceServer(ctx, url, server)
+}
+
+// UpdateServerWithVersion replaces a server outside of any transaction, to
+// persist in the configuration a change already applied through the runtime
+// server endpoint.
+func (c *Client) UpdateServerWithVersion(ctx context.Context, version int, backendName string, server models.Server) (*models.Server, error) {
+	url := c.sectionURL("backend", backendName, "servers", server.Name, url.Values{"version": {strconv.Itoa(version)}})
+	return c.replaceServer(ctx, url, server)
+}
+
+func (c *Client) replaceServer(ctx context.Context, serverURL string, server models.Server) (*models.Server, error) {
+	bodyStr, _ := json.Marshal(server)
+	req, err := http.NewRequestWithContext(ctx, "PUT", serverURL, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.Server{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) DeleteServer(ctx context.Context, transactionId string, backendName string, server models.Server) error {
+	url := c.sectionURL("backend", backendName, "servers", server.Name, transactionQuery(transactionId))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendRequest(req, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) GetRuntimeServer(ctx context.Context, backendName string, serverName string) (*models.RuntimeServer, error) {
+	url := c.runtimeServerURL(backendName, serverName)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.RuntimeServer{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) UpdateRuntimeServer(ctx context.Context, backendName string, server models.RuntimeServer) (*models.RuntimeServer, error) {
+	url := c.runtimeServerURL(backendName, server.Name)
+	bodyStr, _ := json.Marshal(server)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(bodyStr))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res := models.RuntimeServer{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -1,10 +1,13 @@
 package haproxy
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
-func (c *Client) TestApiCall() error {
+func (c *Client) TestApiCall(ctx context.Context) error {
 	url := c.base_url + "/services/haproxy/stats/native"
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,49 @@
+package haproxy
+
+import (
+	"context"
+
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func (c *Client) GetTcpRequestRules(ctx context.Context, transactionId string, parentType string, parentName string) ([]models.TcpRule, error) {
+	return c.getTcpRules(ctx, transactionId, "tcp_request_rules", parentType, parentName)
+}
+
+func (c *Client) ReplaceTcpRequestRules(ctx context.Context, transactionId string, parentType string, parentName string, rules []models.TcpRule) error {
+	return c.replaceTcpRules(ctx, transactionId, "tcp_request_rules", parentType, parentName, rules)
+}
+
+func (c *Client) GetTcpResponseRules(ctx context.Context, transactionId string, parentType string, parentName string) ([]models.TcpRule, error) {
+	return c.getTcpRules(ctx, transactionId, "tcp_response_rules", parentType, parentName)
+}
+
+func (c *Client) ReplaceTcpResponseRules(ctx context.Context, transactionId string, parentType string, parentName string, rules []models.TcpRule) error {
+	return c.replaceTcpRules(ctx, transactionId, "tcp_response_rules", parentType, parentName, rules)
+}
+
+func (c *Client) getTcpRules(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string) ([]models.TcpRule, error) {
+	res := []models.TcpRule{}
+	if err := c.getRules(ctx, transactionId, endpoint, parentType, parentName, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) replaceTcpRules(ctx context.Context, transactionId string, endpoint string, parentType string, parentName string, rules []models.TcpRule) error {
+	existing, err := c.getTcpRules(ctx, transactionId, endpoint, parentType, parentName)
+	if err != nil {
+		return err
+	}
+
+	list := make([]interface{}, 0, len(rules))
+	for i := range rules {
+		index := i
+		rule := rules[i]
+		rule.Index = &index
+		list = append(list, rule)
+	}
+
+	return c.replaceRules(ctx, transactionId, endpoint, parentType, parentName, len(existing), list)
+}
@@ -0,0 +1,102 @@
+package haproxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TLSOptions configures how the client authenticates the Data Plane API and
+// itself when talking HTTPS. Certificates and keys are PEM encoded.
+type TLSOptions struct {
+	// CAFile and CAPEM add a certificate authority to trust, from a file or
+	// inline. The system trust store is used when both are empty.
+	CAFile string
+	CAPEM  string
+	// ClientCert and ClientKey are a client certificate and its key, each
+	// given either inline or as the path of a file.
+	ClientCert string
+	ClientKey  string
+	// ServerName overrides the name the server certificate is verified
+	// against, e.g. when server_addr is an IP address.
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// Config builds the tls.Config described by the options.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" || o.CAPEM != "" {
+		caPEM := []byte(o.CAPEM)
+		if o.CAFile != "" {
+			content, err := os.ReadFile(o.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("error on reading ca_file: %s", err)
+			}
+			caPEM = content
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("no valid PEM certificate found in the certificate authority")
+		}
+		config.RootCAs = pool
+	}
+
+	if o.ClientCert != "" || o.ClientKey != "" {
+		if o.ClientCert == "" || o.ClientKey == "" {
+			return nil, errors.New("client_cert and client_key must be set together")
+		}
+
+		certPEM, err := pemOrFile(o.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("error on reading client_cert: %s", err)
+		}
+		keyPEM, err := pemOrFile(o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error on reading client_key: %s", err)
+		}
+
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// pemOrFile returns value itself when it holds PEM data, or the content of
+// the file it points to otherwise.
+func pemOrFile(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// SetTLSConfig makes the client use config for HTTPS connections.
+func (c *Client) SetTLSConfig(config *tls.Config) {
+	if len(c.nodes) > 0 {
+		for _, node := range c.nodes {
+			node.SetTLSConfig(config)
+		}
+		return
+	}
+
+	transport, ok := c.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		c.HTTPClient.Transport = transport
+	}
+	transport.TLSClientConfig = config
+}
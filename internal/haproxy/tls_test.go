@@ -0,0 +1,153 @@
+package haproxy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCertificate returns a PEM encoded certificate and key signed by
+// parent, or self-signed when parent is nil.
+func newTestCertificate(t *testing.T, name string, parent *tls.Certificate) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, err = x509.ParseCertificate(parent.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signerKey = parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+// newMutualTLSServer starts a server requiring client certificates signed by
+// the returned certificate authority.
+func newMutualTLSServer(t *testing.T) (*httptest.Server, tls.Certificate) {
+	caCert, caKey := newTestCertificate(t, "test-ca", nil)
+	ca, err := tls.X509KeyPair([]byte(caCert), []byte(caKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM([]byte(caCert))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server, ca
+}
+
+func serverCAPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func newTLSTestClient(t *testing.T, server *httptest.Server, options TLSOptions) *Client {
+	config, err := options.Config()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client, err := NewClient("admin", "adminpwd", server.URL, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTLSConfig(config)
+	return client
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	server, ca := newMutualTLSServer(t)
+	cert, key := newTestCertificate(t, "terraform", &ca)
+
+	// the certificate is given inline and the key as a file
+	keyFile := filepath.Join(t.TempDir(), "client.key")
+	if err := os.WriteFile(keyFile, []byte(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTLSTestClient(t, server, TLSOptions{
+		CAPEM:      serverCAPEM(server),
+		ClientCert: cert,
+		ClientKey:  keyFile,
+	})
+
+	if err := client.TestApiCall(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestTLSWithoutClientCertificateFails(t *testing.T) {
+	server, _ := newMutualTLSServer(t)
+
+	client := newTLSTestClient(t, server, TLSOptions{CAPEM: serverCAPEM(server)})
+
+	if err := client.TestApiCall(context.Background()); err == nil {
+		t.Fatal("expected the handshake to fail without a client certificate")
+	}
+}
+
+func TestTLSUnknownAuthorityFails(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	client := newTLSTestClient(t, server, TLSOptions{})
+	if err := client.TestApiCall(context.Background()); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Fatalf("expected a certificate verification error, got %v", err)
+	}
+
+	client = newTLSTestClient(t, server, TLSOptions{InsecureSkipVerify: true})
+	if err := client.TestApiCall(context.Background()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the request to reach the server, got %v", err)
+	}
+}
+
+func TestTLSOptionsRequireCertificateAndKey(t *testing.T) {
+	if _, err := (TLSOptions{ClientCert: "client.crt"}).Config(); err == nil {
+		t.Fatal("expected an error for a client certificate without key")
+	}
+}
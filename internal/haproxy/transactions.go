@@ -1,15 +1,21 @@
 package haproxy
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
 )
 
-func (c *Client) CreateTransaction(version int) (*models.Transaction, error) {
+func (c *Client) CreateTransaction(ctx context.Context, version int) (*models.Transaction, error) {
+	if len(c.nodes) > 0 {
+		return c.createClusterTransaction(ctx)
+	}
+
 	url := c.base_url + "/services/haproxy/transactions?version=" + strconv.Itoa(version)
-	req, err := http.NewRequest("POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -22,9 +28,13 @@ func (c *Client) CreateTransaction(version int) (*models.Transaction, error) {
 	return &res, nil
 }
 
-func (c *Client) CommitTransaction(transactionId string) (*models.Transaction, error) {
-	url := c.base_url + "/services/haproxy/transactions/" + transactionId
-	req, err := http.NewRequest("PUT", url, nil)
+func (c *Client) CommitTransaction(ctx context.Context, transactionId string) (*models.Transaction, error) {
+	if len(c.nodes) > 0 {
+		return c.commitClusterTransaction(ctx, transactionId)
+	}
+
+	url := c.base_url + "/services/haproxy/transactions/" + escapePathSegment(transactionId)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -36,3 +46,110 @@ func (c *Client) CommitTransaction(transactionId string) (*models.Transaction, e
 
 	return &res, nil
 }
+
+func (c *Client) DeleteTransaction(ctx context.Context, transactionId string) error {
+	if len(c.nodes) > 0 {
+		return c.deleteClusterTransaction(ctx, transactionId)
+	}
+
+	url := c.base_url + "/services/haproxy/transactions/" + escapePathSegment(transactionId)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendRequest(req, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) GetTransaction(ctx context.Context, transactionId string) (*models.Transaction, error) {
+	url := c.base_url + "/services/haproxy/transactions/" + escapePathSegment(transactionId)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := models.Transaction{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ListTransactions returns the transactions known by the Data Plane API,
+// filtered by status when status is not empty.
+func (c *Client) ListTransactions(ctx context.Context, status string) ([]models.Transaction, error) {
+	url := c.base_url + "/services/haproxy/transactions"
+	if status != "" {
+		url += "?status=" + status
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []models.Transaction{}
+	if err := c.sendRequest(req, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// RollbackTransaction deletes a transaction that won't be committed. A
+// transaction already gone is not an error.
+func (c *Client) RollbackTransaction(ctx context.Context, transactionId string) error {
+	err := c.DeleteTransaction(ctx, transactionId)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// CleanupStaleTransactions deletes the in_progress transactions opened on a
+// configuration version at least threshold versions older than the current
+// one. Those can no longer be committed and are typically left behind by
+// interrupted runs. The Data Plane API doesn't expose when a transaction was
+// opened, so the age is measured in configuration versions.
+func (c *Client) CleanupStaleTransactions(ctx context.Context, threshold int) ([]string, error) {
+	if len(c.nodes) > 0 {
+		deleted := []string{}
+		for _, node := range c.nodes {
+			ids, err := node.CleanupStaleTransactions(ctx, threshold)
+			for _, id := range ids {
+				deleted = append(deleted, node.endpoint+": "+id)
+			}
+			if err != nil {
+				return deleted, err
+			}
+		}
+		return deleted, nil
+	}
+
+	configuration, err := c.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := c.ListTransactions(ctx, "in_progress")
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []string{}
+	for _, transaction := range transactions {
+		if configuration.Version-transaction.Version < threshold {
+			continue
+		}
+		if err := c.RollbackTransaction(ctx, transaction.Id); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, transaction.Id)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,63 @@
+package haproxy
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeStaleTransactionAPI struct {
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (f *fakeStaleTransactionAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/configuration/raw"):
+		w.Write([]byte(`{"_version": 5, "data": ""}`))
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/transactions"):
+		if r.URL.Query().Get("status") != "in_progress" {
+			http.Error(w, "unexpected status filter", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`[
+			{"_version": 3, "id": "tx-old", "status": "in_progress"},
+			{"_version": 4, "id": "tx-previous", "status": "in_progress"},
+			{"_version": 5, "id": "tx-current", "status": "in_progress"}
+		]`))
+	case r.Method == "DELETE" && strings.Contains(r.URL.Path, "/transactions/"):
+		f.mu.Lock()
+		f.deleted = append(f.deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestCleanupStaleTransactions(t *testing.T) {
+	api := &fakeStaleTransactionAPI{}
+	client := newTestClient(t, api)
+
+	deleted, err := client.CleanupStaleTransactions(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"tx-old"}
+	if !reflect.DeepEqual(deleted, expected) || !reflect.DeepEqual(api.deleted, expected) {
+		t.Fatalf("expected %v to be deleted, got %v (api saw %v)", expected, deleted, api.deleted)
+	}
+}
+
+func TestRollbackTransactionIgnoresMissingTransaction(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	if err := client.RollbackTransaction(context.Background(), "tx-gone"); err != nil {
+		t.Fatalf("expected no error for a transaction already gone, got %s", err)
+	}
+}
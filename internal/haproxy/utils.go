@@ -11,8 +11,11 @@ func basicAuth(username, password string) string {
 	return base64.StdEncoding.EncodeToString([]byte(auth))
 }
 
-func encodeUrl(s string) string {
-	return url.QueryEscape(s)
+// escapePathSegment escapes s to be used as a single segment of a path, so a
+// name or key containing '/', '?', '%', spaces or unicode reaches the Data
+// Plane API as is. Query parameters are encoded with url.Values instead.
+func escapePathSegment(s string) string {
+	return url.PathEscape(s)
 }
 
 func ExtractStringWithRegex(value string, regex string) string {
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+func dataSourceMap() *schema.Resource {
+	return &schema.Resource{
+		Description: "`haproxy_map` read the entries of a runtime map without managing them.",
+		ReadContext: dataSourceMapRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The HAProxy map name. More informations : https://www.haproxy.com/fr/blog/introduction-to-haproxy-maps/",
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					return validation.StringIsNotWhiteSpace(i, s)
+				},
+			},
+			"key_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "If set, only the entries whose key starts with it are returned",
+			},
+			"key_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "If set, only the entries whose key matches this regular expression are returned",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"entries": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Entries of the map, by key",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceMapRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	mapName := d.Get("name").(string)
+
+	mapEntries, err := client.GetMapEntries(ctx, mapName)
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	prefix := d.Get("key_prefix").(string)
+	var keyRegex *regexp.Regexp
+	if v := d.Get("key_regex").(string); v != "" {
+		keyRegex = regexp.MustCompile(v)
+	}
+
+	entries := map[string]interface{}{}
+	for _, entrie := range mapEntries {
+		if !strings.HasPrefix(entrie.Key, prefix) {
+			continue
+		}
+		if keyRegex != nil && !keyRegex.MatchString(entrie.Key) {
+			continue
+		}
+		entries[entrie.Key] = entrie.Value
+	}
+
+	d.SetId(mapName)
+	d.Set("entries", entries)
+	return nil
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceMap(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "haproxy_map" "test" {
+	name    = "test"
+	entries = {
+		"/data-source/a" = "1"
+		"/data-source/b" = "2"
+		"/other"         = "3"
+	}
+}
+
+data "haproxy_map" "test" {
+	name       = haproxy_map.test.name
+	key_prefix = "/data-source/"
+	key_regex  = "a$"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.haproxy_map.test", "entries.%", "1"),
+					resource.TestCheckResourceAttr("data.haproxy_map.test", "entries./data-source/a", "1"),
+				),
+			},
+		},
+	})
+}
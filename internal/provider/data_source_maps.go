@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+func dataSourceMaps() *schema.Resource {
+	return &schema.Resource{
+		Description: "`haproxy_maps` list the maps loaded by HAProxy.",
+		ReadContext: dataSourceMapsRead,
+
+		Schema: map[string]*schema.Schema{
+			"maps": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Maps loaded by HAProxy",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Map name, the file name without its extension, as used by `haproxy_map` and `haproxy_maps`",
+						},
+						"file": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Path of the map file",
+						},
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Runtime ID of the map",
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of entries of the map, counted from its runtime entries",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Description of the map",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceMapsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	runtimeMaps, err := client.GetRuntimeMaps(ctx)
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	maps := []interface{}{}
+	for _, runtimeMap := range runtimeMaps {
+		name := strings.TrimSuffix(path.Base(runtimeMap.File), path.Ext(runtimeMap.File))
+
+		// the runtime listing doesn't report the size of the maps
+		entries, err := client.GetMapEntries(ctx, name)
+		if err != nil {
+			return apiDiagnostics(d, err)
+		}
+
+		maps = append(maps, map[string]interface{}{
+			"name":        name,
+			"file":        runtimeMap.File,
+			"id":          runtimeMap.Id,
+			"size":        len(entries),
+			"description": runtimeMap.Description,
+		})
+	}
+
+	d.SetId("maps")
+	d.Set("maps", maps)
+	return nil
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceMaps(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "haproxy_map" "sized" {
+	name    = "data_source_maps"
+	entries = {
+		"/sized/a" = "1"
+		"/sized/b" = "2"
+	}
+}
+
+data "haproxy_maps" "all" {
+	depends_on = [haproxy_map.sized]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.haproxy_maps.all", "maps.*", map[string]string{
+						"name": "test",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.haproxy_maps.all", "maps.*", map[string]string{
+						"name": "data_source_maps",
+						"size": "2",
+					}),
+				),
+			},
+		},
+	})
+}
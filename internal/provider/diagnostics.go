@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+// apiDiagnostics turns an error of the haproxy client into diagnostics. Data
+// Plane API errors get a summary naming what went wrong and, for validation
+// failures, the path of the attribute the API rejected. Failures on a
+// cluster report the result of every node.
+func apiDiagnostics(d *schema.ResourceData, err error) diag.Diagnostics {
+	var clusterErr *haproxy.ClusterError
+	if errors.As(err, &clusterErr) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Change failed on part of the HAProxy nodes",
+			Detail:   clusterErr.Error(),
+		}}
+	}
+
+	apiErr, ok := haproxy.AsAPIError(err)
+	if !ok {
+		return diag.FromErr(err)
+	}
+
+	diagnostic := diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  apiErrorSummary(apiErr),
+		Detail:   fmt.Sprintf("%s %s returned %d: %s", apiErr.Method, apiErr.Path, apiErr.StatusCode, apiErr.Message),
+	}
+
+	if attribute := apiErrorAttribute(d, apiErr); attribute != "" {
+		diagnostic.AttributePath = cty.GetAttrPath(attribute)
+	}
+
+	return diag.Diagnostics{diagnostic}
+}
+
+func apiErrorSummary(err *haproxy.APIError) string {
+	switch {
+	case errors.Is(err, haproxy.ErrBadRequest):
+		return "Invalid configuration rejected by the Dataplane API"
+	case errors.Is(err, haproxy.ErrUnauthorized):
+		return "Authentication to the Dataplane API failed, check username and password"
+	case errors.Is(err, haproxy.ErrForbidden):
+		return "Operation forbidden by the Dataplane API"
+	case errors.Is(err, haproxy.ErrNotAcceptable):
+		return "Change not acceptable by the Dataplane API"
+	case errors.Is(err, haproxy.ErrVersionMismatch):
+		return "Configuration changed concurrently, version mismatch"
+	case errors.Is(err, haproxy.ErrConflict):
+		return "Object already exists in the Dataplane API"
+	case errors.Is(err, haproxy.ErrNotFound):
+		return "Object not found in the Dataplane API"
+	}
+	return fmt.Sprintf("Dataplane API error %d", err.StatusCode)
+}
+
+// apiErrorAttribute maps the field named by a validation failure, e.g.
+// "balance.algorithm" or "http-check", to the top-level attribute of the
+// resource it comes from, if the resource has one.
+func apiErrorAttribute(d *schema.ResourceData, err *haproxy.APIError) string {
+	field := err.Field()
+	if field == "" || d == nil {
+		return ""
+	}
+
+	attribute := strings.ReplaceAll(strings.SplitN(field, ".", 2)[0], "-", "_")
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.Type().IsObjectType() || !config.Type().HasAttribute(attribute) {
+		return ""
+	}
+	return attribute
+}
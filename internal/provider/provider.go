@@ -2,9 +2,13 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
 )
 
@@ -13,9 +17,25 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"server_addr": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("HAPROXY_SERVER", nil),
-				Description: "HAProxy Dataplaneapi server address.",
+				Description: "HAProxy Dataplaneapi server address. Either 'host:port', a full URL with scheme and optional base path such as 'https://lb.example.com/dataplane', or 'unix:///path/to/dataplane.sock' for a Unix socket. The API version is appended to it. Required unless 'endpoints' is set.",
+			},
+			"endpoints": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Dataplaneapi server addresses of every node of an HAProxy cluster, in the same forms as 'server_addr' which they replace. Every change is applied to each node in order, in its own transaction, and reads detect drift on any node. All nodes share the credentials and TLS settings.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"partial_failure": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      haproxy.PartialFailureFail,
+				Description:  "What to do when a change fails on some of the 'endpoints'. Possible value : 'fail' stops at the first failing node, 'rollback' also restores the configuration of the nodes already changed unless it changed again since the commit, 'continue' applies the change to every other node and only logs a warning, failing if no node succeeded. Runtime changes such as map entries are not rolled back. Default value 'fail'",
+				ValidateFunc: validation.StringInSlice([]string{haproxy.PartialFailureFail, haproxy.PartialFailureRollback, haproxy.PartialFailureContinue}, false),
 			},
 			"username": {
 				Type:        schema.TypeString,
@@ -32,13 +52,111 @@ func Provider() *schema.Provider {
 			"insecure": {
 				Type:        schema.TypeBool,
 				Required:    true,
-				Description: "Scheme for request. If not set, https will be use.",
+				Description: "Scheme for request. If true, plain http is used, otherwise https. Ignored when 'server_addr' is a full URL. It doesn't disable the verification of the server certificate, see 'insecure_skip_verify'.",
 				DefaultFunc: schema.EnvDefaultFunc("HAPROXY_INSECURE", nil),
 			},
+			"api_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("HAPROXY_API_VERSION", haproxy.APIVersionAuto),
+				Description:  "Version of the Dataplane API. Possible value : 'auto', 'v2' or 'v3'. With 'auto', '/v3/info' then '/v2/info' are probed when the provider is configured and the first version answering is used. Default value 'auto'",
+				ValidateFunc: validation.StringInSlice([]string{haproxy.APIVersionAuto, haproxy.APIVersionV2, haproxy.APIVersionV3}, false),
+			},
+			"ca_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("HAPROXY_CA_FILE", ""),
+				Description:   "Path of a PEM encoded certificate authority used to verify the Dataplane API certificate instead of the system trust store.",
+				ConflictsWith: []string{"ca_pem"},
+			},
+			"ca_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM encoded certificate authority used to verify the Dataplane API certificate instead of the system trust store.",
+				ConflictsWith: []string{"ca_file"},
+			},
+			"client_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("HAPROXY_CLIENT_CERT", ""),
+				Description:  "PEM encoded client certificate, or the path of a file containing it, presented to the Dataplane API.",
+				RequiredWith: []string{"client_key"},
+			},
+			"client_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				DefaultFunc:  schema.EnvDefaultFunc("HAPROXY_CLIENT_KEY", ""),
+				Description:  "PEM encoded private key of 'client_cert', or the path of a file containing it.",
+				RequiredWith: []string{"client_cert"},
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HAPROXY_TLS_SERVER_NAME", ""),
+				Description: "Name the Dataplane API certificate is verified against. Defaults to the host of 'server_addr'.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HAPROXY_INSECURE_SKIP_VERIFY", false),
+				Description: "If true, the Dataplane API certificate is not verified. Default value false",
+			},
+			"transaction_batch_window": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      250,
+				Description:  "Time in milliseconds a transaction stays open for other changes once the last change using it is done. Configuration changes made concurrently during an apply share a single transaction, committed once. Default value 250",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      haproxy.DefaultRetryAttempts,
+				Description:  "Maximum number of attempts of a change failing with a version mismatch (409), a 406, a 5xx or a network error. Other errors, such as a 409 for an object already existing, are not retried. Default value 10",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(haproxy.DefaultRetryMaxWait / time.Millisecond),
+				Description:  "Maximum time in milliseconds waited between two attempts. The wait starts at 100ms, doubles on every attempt and is jittered. Default value 5000",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"cleanup_stale_transactions": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, in_progress transactions left behind by interrupted runs are deleted when the provider is configured.",
+			},
+			"stale_transaction_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "Number of configuration versions an in_progress transaction must lag behind the current version to be considered stale by 'cleanup_stale_transactions'. The Dataplane API does not expose when a transaction was opened, so its age is measured in versions. Default value 1",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"haproxy_maps":     resourceMaps(),
-			"haproxy_frontend": resourceFrontend(),
+			"haproxy_maps":                   resourceMaps(),
+			"haproxy_map":                    resourceMap(),
+			"haproxy_map_file":               resourceMapFile(),
+			"haproxy_frontend":               resourceFrontend(),
+			"haproxy_backend":                resourceBackend(),
+			"haproxy_server":                 resourceServer(),
+			"haproxy_bind":                   resourceBind(),
+			"haproxy_acl":                    resourceAcl(),
+			"haproxy_acl_file":               resourceAclFile(),
+			"haproxy_acl_file_entry":         resourceAclFileEntry(),
+			"haproxy_http_request_rules":     resourceHttpRequestRules(),
+			"haproxy_http_response_rules":    resourceHttpResponseRules(),
+			"haproxy_tcp_request_rules":      resourceTcpRequestRules(),
+			"haproxy_tcp_response_rules":     resourceTcpResponseRules(),
+			"haproxy_backend_switching_rule": resourceBackendSwitchingRule(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"haproxy_map":  dataSourceMap(),
+			"haproxy_maps": dataSourceMaps(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -50,13 +168,75 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	password := d.Get("password").(string)
 	insecure := d.Get("insecure").(bool)
 
-	apiClient := haproxy.NewClient(username, password, server_addr, insecure)
+	endpoints := []string{}
+	for _, endpoint := range d.Get("endpoints").([]interface{}) {
+		endpoints = append(endpoints, endpoint.(string))
+	}
+	if len(endpoints) == 0 {
+		if server_addr == "" {
+			return nil, diag.Errorf("one of server_addr or endpoints must be set")
+		}
+		endpoints = append(endpoints, server_addr)
+	}
+
+	apiVersion := d.Get("api_version").(string)
+	nodes := []*haproxy.Client{}
+	for _, endpoint := range endpoints {
+		node, err := haproxy.NewClient(username, password, endpoint, insecure, apiVersion)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		nodes = append(nodes, node)
+	}
+
+	apiClient := nodes[0]
+	if len(nodes) > 1 {
+		apiClient = haproxy.NewCluster(nodes, d.Get("partial_failure").(string))
+	}
 
-	err := apiClient.TestApiCall()
+	tlsConfig, err := haproxy.TLSOptions{
+		CAFile:             d.Get("ca_file").(string),
+		CAPEM:              d.Get("ca_pem").(string),
+		ClientCert:         d.Get("client_cert").(string),
+		ClientKey:          d.Get("client_key").(string),
+		ServerName:         d.Get("tls_server_name").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+	}.Config()
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
+	apiClient.SetTLSConfig(tlsConfig)
+
+	apiClient.TransactionBatchWindow = time.Duration(d.Get("transaction_batch_window").(int)) * time.Millisecond
+	apiClient.RetryAttempts = uint(d.Get("retry_attempts").(int))
+	apiClient.RetryMaxWait = time.Duration(d.Get("retry_max_wait").(int)) * time.Millisecond
+
+	if apiVersion == haproxy.APIVersionAuto {
+		if _, err := apiClient.DetectAPIVersion(ctx); err != nil {
+			return nil, apiDiagnostics(nil, err)
+		}
+	}
+
+	err = apiClient.TestApiCall(ctx)
+	if err != nil {
+		return nil, apiDiagnostics(nil, err)
+	}
+
+	var diags diag.Diagnostics
+	if d.Get("cleanup_stale_transactions").(bool) {
+		deleted, err := apiClient.CleanupStaleTransactions(ctx, d.Get("stale_transaction_threshold").(int))
+		if err != nil {
+			return nil, apiDiagnostics(nil, err)
+		}
+		if len(deleted) > 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Deleted %d stale transactions", len(deleted)),
+				Detail:   "Stale in_progress transactions were rolled back: " + strings.Join(deleted, ", "),
+			})
+		}
+	}
 
-	return apiClient, nil
+	return apiClient, diags
 
 }
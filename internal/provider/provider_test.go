@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"os"
 	"strconv"
 	"testing"
@@ -29,9 +30,20 @@ func TestMain(m *testing.M) {
 	password := os.Getenv("HAPROXY_PASSWORD")
 	insecure, _ := strconv.ParseBool(os.Getenv("HAPROXY_INSECURE"))
 
-	testClient := haproxy.NewClient(username, password, serverAddr, insecure)
+	apiVersion := os.Getenv("HAPROXY_API_VERSION")
 
-	err := testClient.TestApiCall()
+	testClient, err := haproxy.NewClient(username, password, serverAddr, insecure, apiVersion)
+	if err != nil {
+		panic(err)
+	}
+
+	if apiVersion == "" || apiVersion == haproxy.APIVersionAuto {
+		if _, err := testClient.DetectAPIVersion(context.Background()); err != nil {
+			panic(err)
+		}
+	}
+
+	err = testClient.TestApiCall(context.Background())
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func resourceAcl() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_acl` manage ACLs of a frontend or a backend.",
+		CreateContext: resourceAclCreate,
+		ReadContext:   resourceAclRead,
+		UpdateContext: resourceAclUpdate,
+		DeleteContext: resourceAclDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAclImport,
+		},
+		Timeouts: defaultTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"parent_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the frontend or backend the ACL belongs to.",
+			},
+			"parent_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Type of the section the ACL belongs to. Possible value : 'frontend' or 'backend'.",
+				ValidateFunc: validation.StringInSlice([]string{"frontend", "backend"}, false),
+			},
+			"acl_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "ACL name. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#7",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^\s]+$`), "must not contain whitespace"),
+			},
+			"criterion": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Sample fetch and converters the ACL evaluates, e.g. 'src,map_str(/etc/haproxy/maps/test.map)' or 'path_beg'.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Flags and patterns matched against the criterion, e.g. '-m found' or '/api'.",
+			},
+			"index": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Position of the ACL among the ACLs of its parent. If not set, the ACL is appended. Shifts caused by ACLs added or removed elsewhere are not reported as changes.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"current_index": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Position of the ACL in the configuration as of the last refresh.",
+			},
+		},
+	}
+}
+
+func resourceAclImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*haproxy.Client)
+
+	idMatchFormat, _ := regexp.MatchString("^(frontend|backend)/(.+?)/acl/(.+)$", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected <frontend|backend>/<parentName>/acl/<aclName|index>, e.g. frontend/my-frontend/acl/is_api or frontend/my-frontend/acl/0, actual id is %s", d.Id())
+	}
+
+	parentType := haproxy.ExtractStringWithRegex(d.Id(), "^(frontend|backend)/")
+	parentName := haproxy.ExtractStringWithRegex(d.Id(), "^(?:frontend|backend)/(.+?)/acl/")
+	selector := haproxy.ExtractStringWithRegex(d.Id(), "/acl/(.+)$")
+
+	acls, err := client.GetAcls(ctx, "", parentType, parentName)
+	if err != nil {
+		return nil, fmt.Errorf("error on getting acls during import: %s", err)
+	}
+
+	var found *models.Acl
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < 0 || index >= len(acls) {
+			return nil, fmt.Errorf("no acl at index %d in %s %s", index, parentType, parentName)
+		}
+		found = &acls[index]
+	} else {
+		for i := range acls {
+			if acls[i].AclName != selector {
+				continue
+			}
+			if found != nil {
+				return nil, fmt.Errorf("several acls are named %s in %s %s, import it by index instead", selector, parentType, parentName)
+			}
+			found = &acls[i]
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no acl named %s in %s %s", selector, parentType, parentName)
+		}
+	}
+
+	d.SetId(parentType + "/" + parentName + "/acl/" + found.AclName)
+	d.Set("parent_type", parentType)
+	d.Set("parent_name", parentName)
+	d.Set("acl_name", found.AclName)
+	d.Set("criterion", found.Criterion)
+	d.Set("value", found.Value)
+	d.Set("index", *found.Index)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAclRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	acls, err := client.GetAcls(ctx, "", d.Get("parent_type").(string), d.Get("parent_name").(string))
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	acl := models.Acl{
+		AclName:   d.Get("acl_name").(string),
+		Criterion: d.Get("criterion").(string),
+		Value:     d.Get("value").(string),
+	}
+
+	index, ok := findAcl(acls, acl, d.Get("current_index").(int))
+	if !ok {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("acl_name", acls[index].AclName)
+	d.Set("criterion", acls[index].Criterion)
+	d.Set("value", acls[index].Value)
+	d.Set("current_index", index)
+
+	return nil
+}
+
+func resourceAclCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+	acl := buildAclFromResourceParameters(d)
+
+	var index int
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		acls, err := client.GetAcls(ctx, transactionId, parentType, parentName)
+		if err != nil {
+			return err
+		}
+
+		index = insertIndex(d, len(acls))
+		acl.Index = &index
+		_, err = client.CreateAcl(ctx, transactionId, parentType, parentName, acl)
+		return err
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId(parentType + "/" + parentName + "/acl/" + acl.AclName)
+	d.Set("index", index)
+	d.Set("current_index", index)
+	return resourceAclRead(ctx, d, meta)
+}
+
+func resourceAclUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+	acl := buildAclFromResourceParameters(d)
+	previous := previousAcl(d)
+
+	var index int
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		acls, err := client.GetAcls(ctx, transactionId, parentType, parentName)
+		if err != nil {
+			return err
+		}
+
+		current, ok := findAcl(acls, previous, d.Get("current_index").(int))
+		if !ok {
+			return fmt.Errorf("acl %s not found in %s %s", previous.AclName, parentType, parentName)
+		}
+
+		if !d.HasChange("index") {
+			index = current
+			acl.Index = &index
+			_, err = client.UpdateAcl(ctx, transactionId, parentType, parentName, acl)
+			return err
+		}
+
+		// Moving an ACL is a delete followed by an insert at the new
+		// position, both inside the same transaction.
+		err = client.DeleteAcl(ctx, transactionId, parentType, parentName, current)
+		if err != nil {
+			return err
+		}
+		index = insertIndex(d, len(acls)-1)
+		acl.Index = &index
+		_, err = client.CreateAcl(ctx, transactionId, parentType, parentName, acl)
+		return err
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.Set("index", index)
+	d.Set("current_index", index)
+	return resourceAclRead(ctx, d, meta)
+}
+
+func resourceAclDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+	acl := buildAclFromResourceParameters(d)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		acls, err := client.GetAcls(ctx, transactionId, parentType, parentName)
+		if err != nil {
+			return err
+		}
+
+		index, ok := findAcl(acls, acl, d.Get("current_index").(int))
+		if !ok {
+			return nil
+		}
+		return client.DeleteAcl(ctx, transactionId, parentType, parentName, index)
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func buildAclFromResourceParameters(d *schema.ResourceData) models.Acl {
+	return models.Acl{
+		AclName:   d.Get("acl_name").(string),
+		Criterion: d.Get("criterion").(string),
+		Value:     d.Get("value").(string),
+	}
+}
+
+// previousAcl returns the ACL as it was last stored in the state, which is
+// what is expected to be found in the configuration before an update.
+func previousAcl(d *schema.ResourceData) models.Acl {
+	criterion, _ := d.GetChange("criterion")
+	value, _ := d.GetChange("value")
+	return models.Acl{
+		AclName:   d.Get("acl_name").(string),
+		Criterion: criterion.(string),
+		Value:     value.(string),
+	}
+}
+
+// findAcl looks up an ACL by content rather than by index, so ACLs inserted
+// or removed by others don't make the resource point at the wrong line.
+func findAcl(acls []models.Acl, acl models.Acl, hint int) (int, bool) {
+	return findByContent(len(acls), hint,
+		func(i int) bool {
+			return acls[i].AclName == acl.AclName && acls[i].Criterion == acl.Criterion && acls[i].Value == acl.Value
+		},
+		func(i int) bool {
+			return acls[i].AclName == acl.AclName
+		},
+	)
+}
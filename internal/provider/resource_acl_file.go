@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+func resourceAclFile() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_acl_file` manage the entries of an ACL file as a whole at runtime, without reloading HAProxy.",
+		CreateContext: resourceAclFileCreate,
+		ReadContext:   resourceAclFileRead,
+		UpdateContext: resourceAclFileUpdate,
+		DeleteContext: resourceAclFileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAclFileImport,
+		},
+		Timeouts:      defaultTimeouts(),
+		CustomizeDiff: validateAclStorage,
+
+		Schema: map[string]*schema.Schema{
+			"acl_file": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ACL file loaded by HAProxy, by runtime ID, path, file name or file name without extension, e.g. 'blocklist' for '-f /etc/haproxy/acl/blocklist.acl'",
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					return validation.StringIsNotWhiteSpace(i, s)
+				},
+			},
+			"values": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Values of the entries, e.g. IP addresses or networks",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"remove_unmanaged": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, the entries of the ACL file not declared in 'values' are deleted. Otherwise they are left alone. Default value false",
+			},
+			"storage_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "If set, every entry of the ACL file is written to this file of the Dataplane API general storage after each change, so the entries survive a reload. It must be the file HAProxy loads the ACL file from, in the general storage directory. The whole file is rewritten on each change, so every resource of the same ACL file must set the same 'storage_name', which is checked in plan.",
+			},
+		},
+	}
+}
+
+// resourceAclFileImport imports every current entry of the ACL file, given
+// as acl_file/<aclFile>.
+func resourceAclFileImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*haproxy.Client)
+
+	idMatchFormat, _ := regexp.MatchString("^acl_file/.+$", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected acl_file/<aclFile>, e.g. acl_file/blocklist, actual id is %s", d.Id())
+	}
+
+	aclName := strings.TrimPrefix(d.Id(), "acl_file/")
+	aclFile, err := client.GetAclFile(ctx, aclName)
+	if err != nil {
+		return nil, fmt.Errorf("error on getting ACL file during import: %s", err)
+	}
+
+	entries, err := client.GetAclFileEntries(ctx, aclFile.Id)
+	if err != nil {
+		return nil, fmt.Errorf("error on getting ACL file entries during import: %s", err)
+	}
+
+	values := []interface{}{}
+	for _, entry := range entries {
+		values = append(values, entry.Value)
+	}
+
+	d.SetId(aclName)
+	d.Set("acl_file", aclName)
+	d.Set("values", values)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAclFileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	aclName := d.Get("acl_file").(string)
+
+	err := client.Retry(ctx, func() error {
+		return client.SyncAclFileEntries(ctx, aclName, expandAclFileValues(d.Get("values")), haproxy.AclSyncOptions{
+			RemoveUnmanaged: d.Get("remove_unmanaged").(bool),
+		})
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId(aclName)
+
+	if err := persistAclFile(ctx, d, client); err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	return resourceAclFileRead(ctx, d, meta)
+}
+
+func resourceAclFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	aclFile, err := client.GetAclFile(ctx, d.Id())
+
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	entries, err := client.GetAclFileEntries(ctx, aclFile.Id)
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	// without remove_unmanaged, entries not declared are not part of the
+	// resource and don't show up as a diff
+	managed := map[string]bool{}
+	for _, value := range expandAclFileValues(d.Get("values")) {
+		managed[value] = true
+	}
+	removeUnmanaged := d.Get("remove_unmanaged").(bool)
+
+	values := []interface{}{}
+	for _, entry := range entries {
+		if managed[entry.Value] || removeUnmanaged {
+			values = append(values, entry.Value)
+		}
+	}
+
+	d.Set("acl_file", d.Id())
+	d.Set("values", values)
+	d.Set("remove_unmanaged", removeUnmanaged)
+	return nil
+}
+
+func resourceAclFileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	if d.HasChanges("values", "remove_unmanaged") {
+		old, _ := d.GetChange("values")
+
+		err := client.Retry(ctx, func() error {
+			return client.SyncAclFileEntries(ctx, d.Id(), expandAclFileValues(d.Get("values")), haproxy.AclSyncOptions{
+				Stale:           expandAclFileValues(old),
+				RemoveUnmanaged: d.Get("remove_unmanaged").(bool),
+			})
+		})
+		if err != nil {
+			return apiDiagnostics(d, err)
+		}
+	}
+
+	if d.HasChanges("values", "remove_unmanaged", "storage_name") {
+		if err := persistAclFile(ctx, d, client); err != nil {
+			return apiDiagnostics(d, err)
+		}
+	}
+
+	return resourceAclFileRead(ctx, d, meta)
+}
+
+func resourceAclFileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	err := client.Retry(ctx, func() error {
+		return client.SyncAclFileEntries(ctx, d.Id(), []string{}, haproxy.AclSyncOptions{
+			Stale: expandAclFileValues(d.Get("values")),
+		})
+	})
+	if err != nil && !errors.Is(err, haproxy.ErrNotFound) {
+		return apiDiagnostics(d, err)
+	}
+
+	if err := persistAclFile(ctx, d, client); err != nil && !errors.Is(err, haproxy.ErrNotFound) {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func expandAclFileValues(values interface{}) []string {
+	res := []string{}
+	for _, value := range values.(*schema.Set).List() {
+		res = append(res, value.(string))
+	}
+	return res
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+func resourceAclFileEntry() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_acl_file_entry` manage an entry of an ACL file at runtime, without reloading HAProxy.",
+		CreateContext: resourceAclFileEntryCreate,
+		ReadContext:   resourceAclFileEntryRead,
+		DeleteContext: resourceAclFileEntryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAclFileEntryImport,
+		},
+		Timeouts:      defaultTimeouts(),
+		CustomizeDiff: validateAclStorage,
+
+		Schema: map[string]*schema.Schema{
+			"acl_file": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ACL file loaded by HAProxy, by runtime ID, path, file name or file name without extension, e.g. 'blocklist' for '-f /etc/haproxy/acl/blocklist.acl'",
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					return validation.StringIsNotWhiteSpace(i, s)
+				},
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Value of the entry, e.g. an IP address or a network",
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					return validation.StringIsNotWhiteSpace(i, s)
+				},
+			},
+			"storage_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "If set, every entry of the ACL file is written to this file of the Dataplane API general storage after each change, so the entries survive a reload. It must be the file HAProxy loads the ACL file from, in the general storage directory. The whole file is rewritten on each change, so every resource of the same ACL file must set the same 'storage_name', which is checked in plan.",
+			},
+		},
+	}
+}
+
+func resourceAclFileEntryImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*haproxy.Client)
+
+	idMatchFormat, _ := regexp.MatchString("acl_file/(.*?)/entry/(.*?)", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected acl_file/<aclFile>/entry/<value>, e.g. acl_file/blocklist/entry/10.0.0.1, actual id is %s", d.Id())
+	}
+
+	aclFile := haproxy.ExtractStringWithRegex(d.Id(), "acl_file/(.*?)/")
+	value := haproxy.ExtractStringWithRegex(d.Id(), "entry/(.*?)$")
+
+	d.SetId(value)
+	d.Set("acl_file", aclFile)
+
+	file, err := client.GetAclFile(ctx, aclFile)
+	if err == nil {
+		_, err = client.GetAclFileEntry(ctx, file.Id, value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error on getting acl file entry during import: %s", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAclFileEntryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	aclName := d.Get("acl_file").(string)
+	value := d.Get("value").(string)
+
+	err := client.Retry(ctx, func() error {
+		aclFile, err := client.GetAclFile(ctx, aclName)
+		if err != nil {
+			return err
+		}
+		_, err = client.GetAclFileEntry(ctx, aclFile.Id, value)
+		if errors.Is(err, haproxy.ErrNotFound) {
+			_, err = client.CreateAclFileEntry(ctx, aclFile.Id, value)
+		}
+		return err
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId(value)
+
+	if err := persistAclFile(ctx, d, client); err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	return resourceAclFileEntryRead(ctx, d, meta)
+}
+
+func resourceAclFileEntryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	aclFile, err := client.GetAclFile(ctx, d.Get("acl_file").(string))
+	if err == nil {
+		_, err = client.GetAclFileEntry(ctx, aclFile.Id, d.Id())
+	}
+
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.Set("value", d.Id())
+	d.Set("acl_file", d.Get("acl_file").(string))
+	return nil
+}
+
+func resourceAclFileEntryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	err := client.Retry(ctx, func() error {
+		aclFile, err := client.GetAclFile(ctx, d.Get("acl_file").(string))
+		if err != nil {
+			return err
+		}
+		entry, err := client.GetAclFileEntry(ctx, aclFile.Id, d.Id())
+		if err != nil {
+			return err
+		}
+		return client.DeleteAclFileEntry(ctx, aclFile.Id, entry.Id)
+	})
+	if err != nil && !errors.Is(err, haproxy.ErrNotFound) {
+		return apiDiagnostics(d, err)
+	}
+
+	if err := persistAclFile(ctx, d, client); err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// persistAclFile writes the ACL file to the storage when storage_name is set.
+// validateAclStorage rejects in plan a storage_name differing from the one
+// of another resource of the same ACL file, as each write replaces the whole
+// storage file.
+func validateAclStorage(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("acl_file") || !d.NewValueKnown("storage_name") {
+		return nil
+	}
+	client := meta.(*haproxy.Client)
+	return client.ClaimAclStorage(ctx, d.Get("acl_file").(string), d.Get("storage_name").(string))
+}
+
+func persistAclFile(ctx context.Context, d *schema.ResourceData, client *haproxy.Client) error {
+	storageName := d.Get("storage_name").(string)
+	if storageName == "" {
+		return nil
+	}
+	return client.Retry(ctx, func() error {
+		return client.PersistAclFile(ctx, d.Get("acl_file").(string), storageName)
+	})
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceAclFileEntry(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAclFileEntry("192.168.10.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_acl_file_entry.test", "acl_file", "blocklist"),
+					resource.TestCheckResourceAttr("haproxy_acl_file_entry.test", "value", "192.168.10.1"),
+				),
+			},
+			{
+				ResourceName:            "haproxy_acl_file_entry.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"storage_name"},
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					value := s.RootModule().Resources["haproxy_acl_file_entry.test"].Primary.Attributes["id"]
+					return fmt.Sprintf("acl_file/%s/entry/%s", "blocklist", value), nil
+				},
+			},
+		},
+	})
+}
+
+func testAccAclFileEntry(value string) string {
+	return fmt.Sprintf(`
+resource "haproxy_acl_file_entry" "test" {
+	acl_file = "blocklist"
+	value    = "%s"
+}
+`, value)
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceAclFile(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAclFile(`"10.0.0.1", "10.0.0.2"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_acl_file.test", "acl_file", "blocklist"),
+					resource.TestCheckResourceAttr("haproxy_acl_file.test", "values.#", "2"),
+				),
+			},
+			{
+				Config: testAccAclFile(`"10.0.0.2", "10.1.0.0/16"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_acl_file.test", "values.#", "2"),
+					resource.TestCheckTypeSetElemAttr("haproxy_acl_file.test", "values.*", "10.1.0.0/16"),
+				),
+			},
+			{
+				ResourceName:            "haproxy_acl_file.test",
+				ImportState:             true,
+				ImportStateId:           "acl_file/blocklist",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"storage_name"},
+			},
+		},
+	})
+}
+
+func testAccAclFile(values string) string {
+	return fmt.Sprintf(`
+resource "haproxy_acl_file" "test" {
+	acl_file     = "blocklist"
+	values       = [%s]
+	storage_name = "blocklist.acl"
+}
+`, values)
+}
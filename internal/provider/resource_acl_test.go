@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceAcl(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAclConfig("tfacc-acl-frontend", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_acl.test", "acl_name", "is_test_ok"),
+					resource.TestCheckResourceAttr("haproxy_acl.test", "current_index", "0"),
+				),
+			},
+			importStep("haproxy_acl.test"),
+			{
+				// inserting another ACL in front shifts the first one without
+				// producing a diff on it
+				Config: testAccAclConfig("tfacc-acl-frontend", `
+resource "haproxy_acl" "first" {
+	parent_type = "frontend"
+	parent_name = haproxy_frontend.test.name
+	acl_name    = "is_api"
+	criterion   = "path_beg"
+	value       = "/api"
+	index       = 0
+	depends_on  = [haproxy_acl.test]
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_acl.first", "current_index", "0"),
+				),
+			},
+			{
+				Config: testAccAclConfig("tfacc-acl-frontend", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_acl.test", "current_index", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAclConfig(frontendName string, extra string) string {
+	return fmt.Sprintf(`
+resource "haproxy_frontend" "test" {
+	name = "%[1]s"
+}
+
+resource "haproxy_acl" "test" {
+	parent_type = "frontend"
+	parent_name = haproxy_frontend.test.name
+	acl_name    = "is_test_ok"
+	criterion   = "src,map_str(/etc/haproxy/maps/test.map)"
+	value       = "-m found"
+}
+%[2]s
+`, frontendName, extra)
+}
@@ -0,0 +1,647 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func resourceBackend() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_backend` manage backend.",
+		CreateContext: resourceBackendCreate,
+		ReadContext:   resourceBackendRead,
+		UpdateContext: resourceBackendUpdate,
+		DeleteContext: resourceBackendDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: defaultTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Backend name",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Set the running mode or protocol of the instance. Possible value 'http' or 'tcp'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-mode",
+				ValidateFunc: validation.StringInSlice([]string{"http", "tcp"}, false),
+			},
+			"adv_check": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Advanced health check method. Possible value 'ssl-hello-chk', 'smtpchk', 'ldap-check', 'mysql-check', 'pgsql-check', 'tcp-check', 'redis-check' or 'httpchk'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-option%20httpchk",
+				ValidateFunc: validation.StringInSlice([]string{"ssl-hello-chk", "smtpchk", "ldap-check", "mysql-check", "pgsql-check", "tcp-check", "redis-check", "httpchk"}, false),
+			},
+			"balance": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Define the load balancing algorithm to be used in a backend. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-balance",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"algorithm": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Load balancing algorithm. Possible value 'roundrobin', 'static-rr', 'leastconn', 'first', 'source', 'uri', 'url_param', 'hdr', 'random', 'rdp-cookie' or 'hash'.",
+							ValidateFunc: validation.StringInSlice([]string{"roundrobin", "static-rr", "leastconn", "first", "source", "uri", "url_param", "hdr", "random", "rdp-cookie", "hash"}, false),
+						},
+						"hash_expression": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Sample expression used by the 'hash' algorithm.",
+						},
+						"hdr_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "HTTP header name used by the 'hdr' algorithm.",
+						},
+						"hdr_use_domain_only": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "If true, the 'hdr' algorithm only hashes the domain part of the header.",
+						},
+						"rdp_cookie_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Cookie name used by the 'rdp-cookie' algorithm.",
+						},
+						"uri_depth": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Number of directories of the URI to hash with the 'uri' algorithm.",
+						},
+						"uri_len": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Number of characters of the URI to hash with the 'uri' algorithm.",
+						},
+						"uri_whole": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "If true, the 'uri' algorithm hashes the whole URI including the query string.",
+						},
+						"url_param": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "URL parameter used by the 'url_param' algorithm.",
+						},
+					},
+				},
+			},
+			"bind_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Limit visibility of an instance to a certain set of processes numbers. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#bind-process",
+			},
+			"check_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set additional check timeout, but only after a connection has been already established. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-timeout%20check",
+			},
+			"connect_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set the maximum time to wait for a connection attempt to a server to succeed. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-timeout%20connect",
+			},
+			"cookie": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Enable cookie-based persistence in a backend. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-cookie",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the cookie which will be monitored, modified or inserted in order to bring persistence.",
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Cookie mode. Possible value 'rewrite', 'insert' or 'prefix'.",
+							ValidateFunc: validation.StringInSlice([]string{"rewrite", "insert", "prefix"}, false),
+						},
+						"domains": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Domains the cookie is valid for.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"dynamic": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Activate dynamic cookies.",
+						},
+						"httponly": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Add an 'HttpOnly' cookie attribute when a cookie is inserted.",
+						},
+						"indirect": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "No cookie will be emitted to a client which already has a valid one for the server which has processed the request.",
+						},
+						"maxidle": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Maximum idle time in seconds of an inserted cookie.",
+						},
+						"maxlife": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Maximum lifetime in seconds of an inserted cookie.",
+						},
+						"nocache": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Mark responses with an inserted cookie as non-cacheable.",
+						},
+						"postonly": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Only insert the cookie on POST requests.",
+						},
+						"preserve": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Do not touch a cookie already emitted by the server.",
+						},
+						"secure": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Add a 'Secure' cookie attribute when a cookie is inserted.",
+						},
+					},
+				},
+			},
+			"forwardfor": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Enable insertion of the X-Forwarded-For header to requests sent to servers. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-option%20forwardfor",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Enable forwardfor. Possible value : 'enabled' or 'disabled'.",
+						},
+						"except": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Network address for which the header will not be added.",
+						},
+						"header": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Header name to use instead of 'X-Forwarded-For'.",
+						},
+						"ifnone": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Only add the header if it is not already present.",
+						},
+					},
+				},
+			},
+			"http_check": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Make HTTP health checks consider response contents or specific status codes. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-http-check%20expect",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "http-check directive, e.g. 'expect', 'disable-on-404' or 'send-state'.",
+						},
+						"exclamation_mark": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "If true, the match is inverted.",
+						},
+						"match": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Keyword indicating how to look for a specific pattern in the response. Possible value 'status', 'rstatus', 'string' or 'rstring'.",
+							ValidateFunc: validation.StringInSlice([]string{"status", "rstatus", "string", "rstring"}, false),
+						},
+						"pattern": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Pattern to look for in the health check response.",
+						},
+					},
+				},
+			},
+			"httpchk_params": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Request sent by 'option httpchk'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-option%20httpchk",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"method": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "HTTP method used by the health check, e.g. 'GET', 'HEAD' or 'OPTIONS'.",
+						},
+						"uri": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "URI referenced in the HTTP health check request.",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "HTTP version used by the health check, e.g. 'HTTP/1.1'.",
+						},
+					},
+				},
+			},
+			"http_connection_mode": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "HAProxy connection mode. Possible value : 'httpclose' or 'http-server-close' or 'http-keep-alive'",
+			},
+			"http_keep_alive_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set the maximum allowed time to wait for a new HTTP request to appear. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-timeout%20http-keep-alive",
+			},
+			"http_request_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set the maximum allowed time to wait for a complete HTTP request. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#timeout%20http-request",
+			},
+			"log_tag": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the log tag to use for all outgoing logs. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-log-tag",
+			},
+			"queue_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set the maximum time to wait in the queue for a connection slot to be free. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-timeout%20queue",
+			},
+			"retries": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set the number of retries to perform on a server after a connection failure. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-retries",
+			},
+			"server_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set the maximum inactivity time on the server side. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-timeout%20server",
+			},
+			"stick_table": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Configure the stickiness table for the current section. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-stick-table",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Type of data the table stores. Possible value 'ip', 'ipv6', 'integer', 'string' or 'binary'.",
+							ValidateFunc: validation.StringInSlice([]string{"ip", "ipv6", "integer", "string", "binary"}, false),
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Required:    true,
+							Description: "Maximum number of entries that can fit in the table.",
+						},
+						"expire": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Maximum duration of an entry in the table since it was last created, refreshed or matched.",
+						},
+						"keylen": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Maximum number of characters stored for 'string' and 'binary' keys.",
+						},
+						"nopurge": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "If true, the oldest entries are not purged when the table is full.",
+						},
+						"peers": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the peers section used to synchronize the table.",
+						},
+						"store": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Comma separated list of data types to store in the table, e.g. 'http_req_rate(10s),conn_cur'.",
+						},
+					},
+				},
+			},
+			"tunnel_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Set the maximum inactivity time on the client and server side for tunnels. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-timeout%20tunnel",
+			},
+		},
+	}
+}
+
+func resourceBackendRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	backend := models.Backend{
+		Name: d.Id(),
+	}
+
+	result, err := client.GetBackend(ctx, backend)
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.Set("name", result.Name)
+	d.Set("mode", result.Mode)
+	d.Set("adv_check", result.AdvCheck)
+	d.Set("balance", flattenBalance(result.Balance))
+	d.Set("bind_process", result.BindProcess)
+	d.Set("check_timeout", result.CheckTimeout)
+	d.Set("connect_timeout", result.ConnectTimeout)
+	d.Set("cookie", flattenCookie(result.Cookie))
+	d.Set("forwardfor", flattenForwardfor(result.Forwardfor))
+	d.Set("http_check", flattenHttpCheck(result.HttpCheck))
+	d.Set("httpchk_params", flattenHttpchkParams(result.HttpchkParams))
+	d.Set("http_connection_mode", result.HttpConnectionMode)
+	d.Set("http_keep_alive_timeout", result.HttpKeepAliveTimeout)
+	d.Set("http_request_timeout", result.HttpRequestTimeout)
+	d.Set("log_tag", result.LogTag)
+	d.Set("queue_timeout", result.QueueTimeout)
+	d.Set("retries", result.Retries)
+	d.Set("server_timeout", result.ServerTimeout)
+	d.Set("stick_table", flattenStickTable(result.StickTable))
+	d.Set("tunnel_timeout", result.TunnelTimeout)
+
+	return nil
+}
+
+func resourceBackendCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	backend := *buildBackendFromResourceParameters(d)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		_, err := client.CreateBackend(ctx, transactionId, backend)
+		return err
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	d.SetId(backend.Name)
+	return resourceBackendRead(ctx, d, meta)
+}
+
+func resourceBackendUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	backend := *buildBackendFromResourceParameters(d)
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		_, err := client.UpdateBackend(ctx, transactionId, backend)
+		return err
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	return resourceBackendRead(ctx, d, meta)
+}
+
+func resourceBackendDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	backend := models.Backend{
+		Name: d.Id(),
+	}
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		return client.DeleteBackend(ctx, transactionId, backend)
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func buildBackendFromResourceParameters(d *schema.ResourceData) *models.Backend {
+	backend := &models.Backend{
+		Name:                 d.Get("name").(string),
+		Mode:                 d.Get("mode").(string),
+		AdvCheck:             d.Get("adv_check").(string),
+		BindProcess:          d.Get("bind_process").(string),
+		CheckTimeout:         d.Get("check_timeout").(int),
+		ConnectTimeout:       d.Get("connect_timeout").(int),
+		HttpConnectionMode:   d.Get("http_connection_mode").(string),
+		HttpKeepAliveTimeout: d.Get("http_keep_alive_timeout").(int),
+		HttpRequestTimeout:   d.Get("http_request_timeout").(int),
+		LogTag:               d.Get("log_tag").(string),
+		QueueTimeout:         d.Get("queue_timeout").(int),
+		Retries:              d.Get("retries").(int),
+		ServerTimeout:        d.Get("server_timeout").(int),
+		TunnelTimeout:        d.Get("tunnel_timeout").(int),
+	}
+
+	if v, ok := singleBlock(d, "balance"); ok {
+		backend.Balance = &models.Balance{
+			Algorithm:        v["algorithm"].(string),
+			HashExpression:   v["hash_expression"].(string),
+			HdrName:          v["hdr_name"].(string),
+			HdrUseDomainOnly: v["hdr_use_domain_only"].(bool),
+			RdpCookieName:    v["rdp_cookie_name"].(string),
+			UriDepth:         v["uri_depth"].(int),
+			UriLen:           v["uri_len"].(int),
+			UriWhole:         v["uri_whole"].(bool),
+			UrlParam:         v["url_param"].(string),
+		}
+	}
+
+	if v, ok := singleBlock(d, "cookie"); ok {
+		cookie := &models.Cookie{
+			Name:     v["name"].(string),
+			Type:     v["type"].(string),
+			Dynamic:  v["dynamic"].(bool),
+			Httponly: v["httponly"].(bool),
+			Indirect: v["indirect"].(bool),
+			Maxidle:  v["maxidle"].(int),
+			Maxlife:  v["maxlife"].(int),
+			Nocache:  v["nocache"].(bool),
+			Postonly: v["postonly"].(bool),
+			Preserve: v["preserve"].(bool),
+			Secure:   v["secure"].(bool),
+		}
+		for _, domain := range v["domains"].([]interface{}) {
+			cookie.Domains = append(cookie.Domains, models.CookieDomain{Value: domain.(string)})
+		}
+		backend.Cookie = cookie
+	}
+
+	if v, ok := singleBlock(d, "forwardfor"); ok {
+		backend.Forwardfor = &models.Forwardfor{
+			Enabled: v["enabled"].(string),
+			Except:  v["except"].(string),
+			Header:  v["header"].(string),
+			Ifnone:  v["ifnone"].(bool),
+		}
+	}
+
+	if v, ok := singleBlock(d, "http_check"); ok {
+		backend.HttpCheck = &models.HttpCheck{
+			Type:            v["type"].(string),
+			ExclamationMark: v["exclamation_mark"].(bool),
+			Match:           v["match"].(string),
+			Pattern:         v["pattern"].(string),
+		}
+	}
+
+	if v, ok := singleBlock(d, "httpchk_params"); ok {
+		backend.HttpchkParams = &models.HttpchkParams{
+			Method:  v["method"].(string),
+			Uri:     v["uri"].(string),
+			Version: v["version"].(string),
+		}
+	}
+
+	if v, ok := singleBlock(d, "stick_table"); ok {
+		backend.StickTable = &models.StickTable{
+			Type:    v["type"].(string),
+			Size:    v["size"].(int),
+			Expire:  v["expire"].(int),
+			Keylen:  v["keylen"].(int),
+			Nopurge: v["nopurge"].(bool),
+			Peers:   v["peers"].(string),
+			Store:   v["store"].(string),
+		}
+	}
+
+	return backend
+}
+
+func flattenBalance(balance *models.Balance) []interface{} {
+	if balance == nil {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"algorithm":           balance.Algorithm,
+			"hash_expression":     balance.HashExpression,
+			"hdr_name":            balance.HdrName,
+			"hdr_use_domain_only": balance.HdrUseDomainOnly,
+			"rdp_cookie_name":     balance.RdpCookieName,
+			"uri_depth":           balance.UriDepth,
+			"uri_len":             balance.UriLen,
+			"uri_whole":           balance.UriWhole,
+			"url_param":           balance.UrlParam,
+		},
+	}
+}
+
+func flattenCookie(cookie *models.Cookie) []interface{} {
+	if cookie == nil {
+		return nil
+	}
+	domains := make([]interface{}, 0, len(cookie.Domains))
+	for _, domain := range cookie.Domains {
+		domains = append(domains, domain.Value)
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"name":     cookie.Name,
+			"type":     cookie.Type,
+			"domains":  domains,
+			"dynamic":  cookie.Dynamic,
+			"httponly": cookie.Httponly,
+			"indirect": cookie.Indirect,
+			"maxidle":  cookie.Maxidle,
+			"maxlife":  cookie.Maxlife,
+			"nocache":  cookie.Nocache,
+			"postonly": cookie.Postonly,
+			"preserve": cookie.Preserve,
+			"secure":   cookie.Secure,
+		},
+	}
+}
+
+func flattenForwardfor(forwardfor *models.Forwardfor) []interface{} {
+	if forwardfor == nil {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"enabled": forwardfor.Enabled,
+			"except":  forwardfor.Except,
+			"header":  forwardfor.Header,
+			"ifnone":  forwardfor.Ifnone,
+		},
+	}
+}
+
+func flattenHttpCheck(httpCheck *models.HttpCheck) []interface{} {
+	if httpCheck == nil {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"type":             httpCheck.Type,
+			"exclamation_mark": httpCheck.ExclamationMark,
+			"match":            httpCheck.Match,
+			"pattern":          httpCheck.Pattern,
+		},
+	}
+}
+
+func flattenHttpchkParams(params *models.HttpchkParams) []interface{} {
+	if params == nil {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"method":  params.Method,
+			"uri":     params.Uri,
+			"version": params.Version,
+		},
+	}
+}
+
+func flattenStickTable(stickTable *models.StickTable) []interface{} {
+	if stickTable == nil {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"type":    stickTable.Type,
+			"size":    stickTable.Size,
+			"expire":  stickTable.Expire,
+			"keylen":  stickTable.Keylen,
+			"nopurge": stickTable.Nopurge,
+			"peers":   stickTable.Peers,
+			"store":   stickTable.Store,
+		},
+	}
+}
@@ -0,0 +1,268 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func resourceBackendSwitchingRule() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_backend_switching_rule` manage use_backend rules of a frontend.",
+		CreateContext: resourceBackendSwitchingRuleCreate,
+		ReadContext:   resourceBackendSwitchingRuleRead,
+		UpdateContext: resourceBackendSwitchingRuleUpdate,
+		DeleteContext: resourceBackendSwitchingRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceBackendSwitchingRuleImport,
+		},
+		Timeouts: defaultTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"frontend": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the frontend the rule belongs to.",
+			},
+			"backend": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Backend to switch to. May be a log-format expression such as '%[req.hdr(host),lower,map(/etc/haproxy/maps/hosts.map)]' to pick the backend from a map. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-use_backend",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"cond": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Condition type. Possible value : 'if' or 'unless'.",
+				ValidateFunc: validation.StringInSlice([]string{"if", "unless"}, false),
+			},
+			"cond_test": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ACL condition, e.g. 'is_api' or '{ req.hdr(host),lower,map(/etc/haproxy/maps/hosts.map) -m found }'.",
+			},
+			"index": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Position of the rule among the use_backend rules of the frontend. If not set, the rule is appended. Shifts caused by rules added or removed elsewhere are not reported as changes.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"current_index": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Position of the rule in the configuration as of the last refresh.",
+			},
+		},
+	}
+}
+
+func resourceBackendSwitchingRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*haproxy.Client)
+
+	idMatchFormat, _ := regexp.MatchString("^frontend/([^/]+)/backend_switching_rule/(.+)$", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected frontend/<frontendName>/backend_switching_rule/<backendName|index>, e.g. frontend/my-frontend/backend_switching_rule/0, actual id is %s", d.Id())
+	}
+
+	frontendName := haproxy.ExtractStringWithRegex(d.Id(), "^frontend/([^/]+)/")
+	selector := haproxy.ExtractStringWithRegex(d.Id(), "/backend_switching_rule/(.+)$")
+
+	rules, err := client.GetBackendSwitchingRules(ctx, "", frontendName)
+	if err != nil {
+		return nil, fmt.Errorf("error on getting backend switching rules during import: %s", err)
+	}
+
+	var found *models.BackendSwitchingRule
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < 0 || index >= len(rules) {
+			return nil, fmt.Errorf("no backend switching rule at index %d in frontend %s", index, frontendName)
+		}
+		found = &rules[index]
+	} else {
+		for i := range rules {
+			if rules[i].Name != selector {
+				continue
+			}
+			if found != nil {
+				return nil, fmt.Errorf("several rules use backend %s in frontend %s, import it by index instead", selector, frontendName)
+			}
+			found = &rules[i]
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no rule uses backend %s in frontend %s", selector, frontendName)
+		}
+	}
+
+	d.SetId("frontend/" + frontendName + "/backend_switching_rule/" + found.Name)
+	d.Set("frontend", frontendName)
+	d.Set("backend", found.Name)
+	d.Set("cond", found.Cond)
+	d.Set("cond_test", found.CondTest)
+	d.Set("index", *found.Index)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceBackendSwitchingRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	rules, err := client.GetBackendSwitchingRules(ctx, "", d.Get("frontend").(string))
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	index, ok := findBackendSwitchingRule(rules, buildBackendSwitchingRuleFromResourceParameters(d), d.Get("current_index").(int))
+	if !ok {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("backend", rules[index].Name)
+	d.Set("cond", rules[index].Cond)
+	d.Set("cond_test", rules[index].CondTest)
+	d.Set("current_index", index)
+
+	return nil
+}
+
+func resourceBackendSwitchingRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	frontendName := d.Get("frontend").(string)
+	rule := buildBackendSwitchingRuleFromResourceParameters(d)
+
+	var index int
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		rules, err := client.GetBackendSwitchingRules(ctx, transactionId, frontendName)
+		if err != nil {
+			return err
+		}
+
+		index = insertIndex(d, len(rules))
+		rule.Index = &index
+		_, err = client.CreateBackendSwitchingRule(ctx, transactionId, frontendName, rule)
+		return err
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("frontend/" + frontendName + "/backend_switching_rule/" + rule.Name)
+	d.Set("index", index)
+	d.Set("current_index", index)
+	return resourceBackendSwitchingRuleRead(ctx, d, meta)
+}
+
+func resourceBackendSwitchingRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	frontendName := d.Get("frontend").(string)
+	rule := buildBackendSwitchingRuleFromResourceParameters(d)
+
+	backend, _ := d.GetChange("backend")
+	cond, _ := d.GetChange("cond")
+	condTest, _ := d.GetChange("cond_test")
+	previous := models.BackendSwitchingRule{
+		Name:     backend.(string),
+		Cond:     cond.(string),
+		CondTest: condTest.(string),
+	}
+
+	var index int
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		rules, err := client.GetBackendSwitchingRules(ctx, transactionId, frontendName)
+		if err != nil {
+			return err
+		}
+
+		current, ok := findBackendSwitchingRule(rules, previous, d.Get("current_index").(int))
+		if !ok {
+			return fmt.Errorf("use_backend %s not found in frontend %s", previous.Name, frontendName)
+		}
+
+		if !d.HasChange("index") {
+			index = current
+			rule.Index = &index
+			_, err = client.UpdateBackendSwitchingRule(ctx, transactionId, frontendName, rule)
+			return err
+		}
+
+		err = client.DeleteBackendSwitchingRule(ctx, transactionId, frontendName, current)
+		if err != nil {
+			return err
+		}
+		index = insertIndex(d, len(rules)-1)
+		rule.Index = &index
+		_, err = client.CreateBackendSwitchingRule(ctx, transactionId, frontendName, rule)
+		return err
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("frontend/" + frontendName + "/backend_switching_rule/" + rule.Name)
+	d.Set("index", index)
+	d.Set("current_index", index)
+	return resourceBackendSwitchingRuleRead(ctx, d, meta)
+}
+
+func resourceBackendSwitchingRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	frontendName := d.Get("frontend").(string)
+	rule := buildBackendSwitchingRuleFromResourceParameters(d)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		rules, err := client.GetBackendSwitchingRules(ctx, transactionId, frontendName)
+		if err != nil {
+			return err
+		}
+
+		index, ok := findBackendSwitchingRule(rules, rule, d.Get("current_index").(int))
+		if !ok {
+			return nil
+		}
+		return client.DeleteBackendSwitchingRule(ctx, transactionId, frontendName, index)
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func buildBackendSwitchingRuleFromResourceParameters(d *schema.ResourceData) models.BackendSwitchingRule {
+	return models.BackendSwitchingRule{
+		Name:     d.Get("backend").(string),
+		Cond:     d.Get("cond").(string),
+		CondTest: d.Get("cond_test").(string),
+	}
+}
+
+// findBackendSwitchingRule looks up a rule by content rather than by index.
+// A rule at the hinted index with the same condition is adopted when nothing
+// matches, so a backend changed out of band shows up as a diff.
+func findBackendSwitchingRule(rules []models.BackendSwitchingRule, rule models.BackendSwitchingRule, hint int) (int, bool) {
+	return findByContent(len(rules), hint,
+		func(i int) bool {
+			return rules[i].Name == rule.Name && rules[i].Cond == rule.Cond && rules[i].CondTest == rule.CondTest
+		},
+		func(i int) bool {
+			return rules[i].Cond == rule.Cond && rules[i].CondTest == rule.CondTest
+		},
+	)
+}
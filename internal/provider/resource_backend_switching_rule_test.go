@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceBackendSwitchingRule(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendSwitchingRuleConfig("tfacc-switching"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_backend_switching_rule.static", "current_index", "0"),
+					resource.TestCheckResourceAttr("haproxy_backend_switching_rule.dynamic", "backend", "%[req.hdr(host),lower,map(/etc/haproxy/maps/test.map)]"),
+					resource.TestCheckResourceAttr("haproxy_backend_switching_rule.dynamic", "current_index", "1"),
+				),
+			},
+			importStep("haproxy_backend_switching_rule.static"),
+			{
+				ResourceName:      "haproxy_backend_switching_rule.dynamic",
+				ImportState:       true,
+				ImportStateId:     "frontend/tfacc-switching/backend_switching_rule/1",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccBackendSwitchingRuleConfig(name string) string {
+	return fmt.Sprintf(`
+resource "haproxy_frontend" "test" {
+	name = "%[1]s"
+}
+
+resource "haproxy_backend" "test" {
+	name = "%[1]s"
+	mode = "http"
+}
+
+resource "haproxy_backend_switching_rule" "static" {
+	frontend  = haproxy_frontend.test.name
+	backend   = haproxy_backend.test.name
+	cond      = "if"
+	cond_test = "{ path_beg /api }"
+}
+
+resource "haproxy_backend_switching_rule" "dynamic" {
+	frontend   = haproxy_frontend.test.name
+	backend    = "%%[req.hdr(host),lower,map(/etc/haproxy/maps/test.map)]"
+	cond       = "if"
+	cond_test  = "{ req.hdr(host),lower,map(/etc/haproxy/maps/test.map) -m found }"
+	depends_on = [haproxy_backend_switching_rule.static]
+}
+`, name)
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceBackend(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig("tfacc-backend1", "roundrobin"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_backend.test", "name", "tfacc-backend1"),
+					resource.TestCheckResourceAttr("haproxy_backend.test", "mode", "http"),
+					resource.TestCheckResourceAttr("haproxy_backend.test", "balance.#", "1"),
+				),
+			},
+			importStep("haproxy_backend.test"),
+			{
+				Config: testAccBackendConfig("tfacc-backend1", "leastconn"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_backend.test", "name", "tfacc-backend1"),
+				),
+			},
+			importStep("haproxy_backend.test"),
+		},
+	})
+}
+
+func testAccBackendConfig(name string, algorithm string) string {
+	return fmt.Sprintf(`
+resource "haproxy_backend" "test" {
+	name            = "%[1]s"
+	mode            = "http"
+	connect_timeout = 5000
+	server_timeout  = 10000
+
+	balance {
+		algorithm = "%[2]s"
+	}
+
+	forwardfor {
+		enabled = "enabled"
+	}
+
+	stick_table {
+		type   = "ip"
+		size   = 100000
+		expire = 30000
+		store  = "http_req_rate(10s)"
+	}
+}
+`, name, algorithm)
+}
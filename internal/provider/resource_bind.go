@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func resourceBind() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_bind` manage listeners attached to a frontend.",
+		CreateContext: resourceBindCreate,
+		ReadContext:   resourceBindRead,
+		UpdateContext: resourceBindUpdate,
+		DeleteContext: resourceBindDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceBindImport,
+		},
+		Timeouts: defaultTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"parent_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the section the bind line belongs to.",
+			},
+			"parent_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "frontend",
+				Description:  "Type of the section the bind line belongs to. Possible value : 'frontend'. Default value 'frontend'",
+				ValidateFunc: validation.StringInSlice([]string{"frontend"}, false),
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Bind name",
+			},
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "IPv4 or IPv6 address to listen on, '*' for all addresses, or the path of a UNIX socket. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-bind",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Port to listen on. Leave unset for UNIX sockets.",
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"port_range_end": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Last port of a port range starting at 'port'.",
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"mode": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Octal mode used to define access permissions on the UNIX socket. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-mode",
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Owner of the UNIX socket. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-user",
+			},
+			"group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Group of the UNIX socket. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-group",
+			},
+			"ssl": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Enable SSL deciphering on connections instantiated from this listener. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-ssl",
+			},
+			"ssl_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of the PEM file or directory containing the certificates. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-crt",
+			},
+			"crt_list": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a certificate list file. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-crt-list",
+			},
+			"alpn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Comma separated list of protocols advertised through ALPN, e.g. 'h2,http/1.1'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-alpn",
+			},
+			"ciphers": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Cipher suites negotiated during the SSL/TLS handshake. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-ciphers",
+			},
+			"accept_proxy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Enforce the use of the PROXY protocol over any connection accepted by this listener. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-accept-proxy",
+			},
+			"v4v6": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Accept both IPv4 and IPv6 connections when binding to the IPv6 wildcard. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-v4v6",
+			},
+			"transparent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Accept connections for a non-local address. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.1-transparent",
+			},
+		},
+	}
+}
+
+func resourceBindImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	idMatchFormat, _ := regexp.MatchString("^frontend/(.+?)/bind/(.+)$", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected frontend/<frontendName>/bind/<bindName>, e.g. frontend/my-frontend/bind/http, actual id is %s", d.Id())
+	}
+
+	d.Set("parent_type", "frontend")
+	d.Set("parent_name", haproxy.ExtractStringWithRegex(d.Id(), "^frontend/(.+?)/bind/"))
+	d.Set("name", haproxy.ExtractStringWithRegex(d.Id(), "/bind/(.+)$"))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceBindRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	bind := models.Bind{
+		Name: d.Get("name").(string),
+	}
+
+	result, err := client.GetBind(ctx, d.Get("parent_type").(string), d.Get("parent_name").(string), bind)
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	port := 0
+	if result.Port != nil {
+		port = *result.Port
+	}
+	portRangeEnd := 0
+	if result.PortRangeEnd != nil {
+		portRangeEnd = *result.PortRangeEnd
+	}
+
+	d.Set("name", result.Name)
+	d.Set("address", result.Address)
+	d.Set("port", port)
+	d.Set("port_range_end", portRangeEnd)
+	d.Set("mode", result.Mode)
+	d.Set("user", result.User)
+	d.Set("group", result.Group)
+	d.Set("ssl", result.Ssl)
+	d.Set("ssl_certificate", result.SslCertificate)
+	d.Set("crt_list", result.CrtList)
+	d.Set("alpn", result.Alpn)
+	d.Set("ciphers", result.Ciphers)
+	d.Set("accept_proxy", result.AcceptProxy)
+	d.Set("v4v6", result.V4v6)
+	d.Set("transparent", result.Transparent)
+
+	return nil
+}
+
+func resourceBindCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+	bind := *buildBindFromResourceParameters(d)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		_, err := client.CreateBind(ctx, transactionId, parentType, parentName, bind)
+		return err
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	d.SetId(parentType + "/" + parentName + "/bind/" + bind.Name)
+	return resourceBindRead(ctx, d, meta)
+}
+
+func resourceBindUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+	bind := *buildBindFromResourceParameters(d)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		_, err := client.UpdateBind(ctx, transactionId, parentType, parentName, bind)
+		return err
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	return resourceBindRead(ctx, d, meta)
+}
+
+func resourceBindDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+
+	bind := models.Bind{
+		Name: d.Get("name").(string),
+	}
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		return client.DeleteBind(ctx, transactionId, parentType, parentName, bind)
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func buildBindFromResourceParameters(d *schema.ResourceData) *models.Bind {
+	bind := &models.Bind{
+		Name:           d.Get("name").(string),
+		Address:        d.Get("address").(string),
+		Mode:           d.Get("mode").(string),
+		User:           d.Get("user").(string),
+		Group:          d.Get("group").(string),
+		Ssl:            d.Get("ssl").(bool),
+		SslCertificate: d.Get("ssl_certificate").(string),
+		CrtList:        d.Get("crt_list").(string),
+		Alpn:           d.Get("alpn").(string),
+		Ciphers:        d.Get("ciphers").(string),
+		AcceptProxy:    d.Get("accept_proxy").(bool),
+		V4v6:           d.Get("v4v6").(bool),
+		Transparent:    d.Get("transparent").(bool),
+	}
+
+	if v, ok := d.GetOk("port"); ok {
+		port := v.(int)
+		bind.Port = &port
+	}
+
+	if v, ok := d.GetOk("port_range_end"); ok {
+		portRangeEnd := v.(int)
+		bind.PortRangeEnd = &portRangeEnd
+	}
+
+	return bind
+}
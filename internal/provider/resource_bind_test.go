@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceBind(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBindConfig("tfacc-bind-frontend", 18080),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_bind.test", "id", "frontend/tfacc-bind-frontend/bind/http"),
+					resource.TestCheckResourceAttr("haproxy_bind.test", "port", "18080"),
+				),
+			},
+			importStep("haproxy_bind.test"),
+			{
+				Config: testAccBindConfig("tfacc-bind-frontend", 18081),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_bind.test", "port", "18081"),
+				),
+			},
+			importStep("haproxy_bind.test"),
+		},
+	})
+}
+
+func testAccBindConfig(frontendName string, port int) string {
+	return fmt.Sprintf(`
+resource "haproxy_frontend" "test" {
+	name = "%[1]s"
+}
+
+resource "haproxy_bind" "test" {
+	parent_name = haproxy_frontend.test.name
+	name        = "http"
+	address     = "*"
+	port        = %[2]d
+	v4v6        = true
+}
+`, frontendName, port)
+}
@@ -2,8 +2,9 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
-	"github.com/avast/retry-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
@@ -20,6 +21,7 @@ func resourceFrontend() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: defaultTimeouts(),
 		Schema: map[string]*schema.Schema{
 			"bind_process": {
 				Type:        schema.TypeString,
@@ -59,6 +61,7 @@ func resourceFrontend() *schema.Resource {
 			"forwardfor": {
 				Type:        schema.TypeSet,
 				Optional:    true,
+				MaxItems:    1,
 				Description: "Enable insertion of the X-Forwarded-For header to requests sent to servers. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-option%20forwardfor",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -153,6 +156,7 @@ func resourceFrontend() *schema.Resource {
 			"monitor_fail": {
 				Type:        schema.TypeSet,
 				Optional:    true,
+				MaxItems:    1,
 				Description: "Add a condition to report a failure to a monitor HTTP request. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-monitor%20fail",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -183,6 +187,7 @@ func resourceFrontend() *schema.Resource {
 			"stats_options": {
 				Type:        schema.TypeSet,
 				Optional:    true,
+				MaxItems:    1,
 				Description: "HAProxy stats options.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -255,36 +260,19 @@ func resourceFrontendRead(ctx context.Context, d *schema.ResourceData, meta inte
 		Name: d.Id(),
 	}
 
-	result, err := client.GetFrontend(frontend)
-	if err != nil {
-		return diag.FromErr(err)
+	result, err := client.GetFrontend(ctx, frontend)
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
 
+	if err != nil {
+		return apiDiagnostics(d, err)
 	}
 
-	d.Set("name", result.Name)
-	d.Set("bind_process", result.BindProcess)
-	d.Set("clflog", result.Clflog)
-	d.Set("client_timeout", result.ClientTimeout)
-	d.Set("clitcpka", result.Clitcpka)
-	d.Set("contstats", result.Contstats)
-	d.Set("default_backend", result.DefaultBackend)
-	d.Set("dontlognull", result.Dontlognull)
-	d.Set("forwardfor", result.Forwardfor)
-	d.Set("http_buffer_request", result.HttpBufferRequest)
-	d.Set("http_use_htx", result.HttpUseHtx)
-	d.Set("http_connection_mode", result.HttpConnectionMode)
-	d.Set("http_keep_alive_timeout", result.HttpKeepAliveTimeout)
-	d.Set("http_request_timeout", result.HttpRequestTimeout)
-	d.Set("httplog", result.HttpLog)
-	d.Set("log_format", result.LogFormat)
-	d.Set("log_format_sd", result.LogFormatSd)
-	d.Set("log_separate_errors", result.LogSeparateErrors)
-	d.Set("log_tag", result.LogTag)
-	d.Set("logasap", result.Logasap)
-	d.Set("maxconn", result.MaxConn)
-	d.Set("mode", result.Mode)
-	d.Set("monitor_fail", result.MonitorFail)
-	d.Set("monitor_uri", result.MonitorUri)
+	if err := flattenFrontend(d, result); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
@@ -293,65 +281,30 @@ func resourceFrontendCreate(ctx context.Context, d *schema.ResourceData, meta in
 	client := meta.(*haproxy.Client)
 	frontend := *buildFrontendFromResourceParameters(d)
 
-	err := retry.Do(
-		func() error {
-			configuration, err := client.GetConfiguration()
-			if err != nil {
-				return err
-			}
-			transaction, err := client.CreateTransaction(configuration.Version)
-			if err != nil {
-				return err
-			}
-
-			_, err = client.CreateFrontend(transaction.Id, frontend)
-			if err != nil {
-				return err
-			}
-			_, err = client.CommitTransaction(transaction.Id)
-			if err != nil {
-				return err
-			}
-			return nil
-		},
-	)
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		_, err := client.CreateFrontend(ctx, transactionId, frontend)
+		return err
+	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiDiagnostics(d, err)
 	}
 	d.SetId(frontend.Name)
-	return nil
+	return resourceFrontendRead(ctx, d, meta)
 }
 
 func resourceFrontendUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*haproxy.Client)
 
 	frontend := *buildFrontendFromResourceParameters(d)
-	err := retry.Do(
-		func() error {
-			configuration, err := client.GetConfiguration()
-			if err != nil {
-				return err
-			}
-			transaction, err := client.CreateTransaction(configuration.Version)
-			if err != nil {
-				return err
-			}
-			_, err = client.UpdateFrontend(transaction.Id, frontend)
-			if err != nil {
-				return err
-			}
-			_, err = client.CommitTransaction(transaction.Id)
-			if err != nil {
-				return err
-			}
-			return nil
-		},
-	)
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		_, err := client.UpdateFrontend(ctx, transactionId, frontend)
+		return err
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		return apiDiagnostics(d, err)
 	}
-	return nil
+	return resourceFrontendRead(ctx, d, meta)
 }
 
 func resourceFrontendDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -359,31 +312,12 @@ func resourceFrontendDelete(ctx context.Context, d *schema.ResourceData, meta in
 
 	frontend := *buildFrontendFromResourceParameters(d)
 
-	err := retry.Do(
-		func() error {
-			configuration, err := client.GetConfiguration()
-			if err != nil {
-				return err
-			}
-			transaction, err := client.CreateTransaction(configuration.Version)
-			if err != nil {
-				return err
-			}
-
-			err = client.DeleteFrontend(transaction.Id, frontend)
-			if err != nil {
-				return err
-			}
-			_, err = client.CommitTransaction(transaction.Id)
-			if err != nil {
-				return err
-			}
-			return nil
-		},
-	)
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		return client.DeleteFrontend(ctx, transactionId, frontend)
+	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiDiagnostics(d, err)
 	}
 
 	d.SetId("")
@@ -391,132 +325,135 @@ func resourceFrontendDelete(ctx context.Context, d *schema.ResourceData, meta in
 }
 
 func buildFrontendFromResourceParameters(d *schema.ResourceData) *models.Frontend {
-	frontend := &models.Frontend{}
-	if v, ok := d.GetOk("bind_process"); ok {
-		frontend.BindProcess = v.(string)
-	}
-
-	if v, ok := d.GetOk("clflog"); ok {
-		frontend.Clflog = v.(bool)
-	}
-
-	if v, ok := d.GetOk("client_timeout"); ok {
-		frontend.ClientTimeout = v.(int)
-	}
-
-	if v, ok := d.GetOk("clitcpka"); ok {
-		frontend.Clitcpka = v.(string)
-	}
-
-	if v, ok := d.GetOk("contstats"); ok {
-		frontend.Contstats = v.(string)
-	}
-
-	if v, ok := d.GetOk("default_backend"); ok {
-		frontend.DefaultBackend = v.(string)
-	}
-
-	if v, ok := d.GetOk("dontlognull"); ok {
-		frontend.Dontlognull = v.(string)
-	}
-
-	if v, ok := d.GetOk("forwardFor"); ok {
-		forwardFor := models.Forwardfor{}
-		forwardFor.Enabled = v.(map[string]interface{})["enabled"].(string)
-		forwardFor.Except = v.(map[string]interface{})["except"].(string)
-		forwardFor.Header = v.(map[string]interface{})["header"].(string)
-		forwardFor.Ifnone = v.(map[string]interface{})["ifnone"].(bool)
-	}
-
-	if v, ok := d.GetOk("http_buffer_request"); ok {
-		frontend.HttpBufferRequest = v.(string)
-	}
-
-	if v, ok := d.GetOk("http_use_htx"); ok {
-		frontend.HttpUseHtx = v.(string)
-	}
-
-	if v, ok := d.GetOk("http_connection_mode"); ok {
-		frontend.HttpConnectionMode = v.(string)
+	frontend := &models.Frontend{
+		Name:                 d.Get("name").(string),
+		BindProcess:          d.Get("bind_process").(string),
+		Clflog:               d.Get("clflog").(bool),
+		ClientTimeout:        d.Get("client_timeout").(int),
+		Clitcpka:             d.Get("clitcpka").(string),
+		Contstats:            d.Get("contstats").(string),
+		DefaultBackend:       d.Get("default_backend").(string),
+		Dontlognull:          d.Get("dontlognull").(string),
+		HttpBufferRequest:    d.Get("http_buffer_request").(string),
+		HttpUseHtx:           d.Get("http_use_htx").(string),
+		HttpConnectionMode:   d.Get("http_connection_mode").(string),
+		HttpKeepAliveTimeout: d.Get("http_keep_alive_timeout").(int),
+		HttpRequestTimeout:   d.Get("http_request_timeout").(int),
+		HttpLog:              d.Get("httplog").(bool),
+		LogFormat:            d.Get("log_format").(string),
+		LogFormatSd:          d.Get("log_format_sd").(string),
+		LogSeparateErrors:    d.Get("log_separate_errors").(string),
+		LogTag:               d.Get("log_tag").(string),
+		Logasap:              d.Get("logasap").(string),
+		MaxConn:              d.Get("maxconn").(int),
+		Mode:                 d.Get("mode").(string),
+		MonitorUri:           d.Get("monitor_uri").(string),
+		TcpLog:               d.Get("tcplog").(bool),
+		UniqueIdFormat:       d.Get("unique_id_format").(string),
+		UniqueIdHeader:       d.Get("unique_id_header").(string),
+	}
+
+	if v, ok := singleBlock(d, "forwardfor"); ok {
+		frontend.Forwardfor = &models.Forwardfor{
+			Enabled: v["enabled"].(string),
+			Except:  v["except"].(string),
+			Header:  v["header"].(string),
+			Ifnone:  v["ifnone"].(bool),
+		}
+	}
+
+	if v, ok := singleBlock(d, "monitor_fail"); ok {
+		frontend.MonitorFail = &models.MonitorFail{
+			Cond:     v["cond"].(string),
+			CondTest: v["cond_test"].(string),
+		}
+	}
+
+	if v, ok := singleBlock(d, "stats_options"); ok {
+		frontend.StatsOptions = &models.StatsOptions{
+			StatsEnable:       v["stats_enable"].(bool),
+			StatsHideVersion:  v["stats_hide_version"].(bool),
+			StatsMaxconn:      v["stats_maxconn"].(int),
+			StatsRefreshDelay: v["stats_refresh_delay"].(int),
+			StatsShowDesc:     v["stats_show_desc"].(string),
+			StatsShowLegends:  v["stats_show_legends"].(bool),
+			StatsShowNodeName: v["stats_show_node_name"].(string),
+			StatsUrilPrefix:   v["stats_uri_prefix"].(string),
+		}
 	}
 
-	if v, ok := d.GetOk("http_keep_alive_timeout"); ok {
-		frontend.HttpKeepAliveTimeout = v.(int)
-	}
-
-	if v, ok := d.GetOk("http_request_timeout"); ok {
-		frontend.HttpRequestTimeout = v.(int)
-	}
-
-	if v, ok := d.GetOk("httplog"); ok {
-		frontend.HttpLog = v.(bool)
-	}
-
-	if v, ok := d.GetOk("log_format"); ok {
-		frontend.LogFormat = v.(string)
-	}
-
-	if v, ok := d.GetOk("log_format_sd"); ok {
-		frontend.LogFormatSd = v.(string)
-	}
-
-	if v, ok := d.GetOk("log_separate_errors"); ok {
-		frontend.LogSeparateErrors = v.(string)
-	}
-
-	if v, ok := d.GetOk("log_tag"); ok {
-		frontend.LogTag = v.(string)
-	}
-
-	if v, ok := d.GetOk("logasap"); ok {
-		frontend.Logasap = v.(string)
-	}
-
-	if v, ok := d.GetOk("maxconn"); ok {
-		frontend.MaxConn = v.(int)
-	}
-
-	if v, ok := d.GetOk("mode"); ok {
-		frontend.Mode = v.(string)
-	}
-
-	if v, ok := d.GetOk("monitor_fail"); ok {
-		monitorFail := models.MonitorFail{}
-		monitorFail.Cond = v.(map[string]interface{})["cond"].(string)
-		monitorFail.CondTest = v.(map[string]interface{})["cond_test"].(string)
-	}
-
-	if v, ok := d.GetOk("monitor_uri"); ok {
-		frontend.MonitorUri = v.(string)
-	}
-
-	if v, ok := d.GetOk("name"); ok {
-		frontend.Name = v.(string)
-	}
+	return frontend
+}
 
-	if v, ok := d.GetOk("stats_options"); ok {
-		statsOptions := models.StatsOptions{}
-		statsOptions.StatsEnable = v.(map[string]interface{})["stats_enable"].(bool)
-		statsOptions.StatsHideVersion = v.(map[string]interface{})["stats_hide_version"].(bool)
-		statsOptions.StatsMaxconn = v.(map[string]interface{})["stats_maxconn"].(int)
-		statsOptions.StatsRefreshDelay = v.(map[string]interface{})["stats_refresh_delay"].(int)
-		statsOptions.StatsShowDesc = v.(map[string]interface{})["stats_show_desc"].(string)
-		statsOptions.StatsShowLegends = v.(map[string]interface{})["stats_show_legends"].(bool)
-		statsOptions.StatsShowNodeName = v.(map[string]interface{})["stats_show_node_name"].(string)
-		statsOptions.StatsUrilPrefix = v.(map[string]interface{})["stats_uri_prefix"].(string)
+// flattenFrontend sets every attribute of d from frontend, the reverse of
+// buildFrontendFromResourceParameters, so plans and imports see the actual
+// configuration of HAProxy.
+func flattenFrontend(d *schema.ResourceData, frontend *models.Frontend) error {
+	values := map[string]interface{}{
+		"name":                    frontend.Name,
+		"bind_process":            frontend.BindProcess,
+		"clflog":                  frontend.Clflog,
+		"client_timeout":          frontend.ClientTimeout,
+		"clitcpka":                frontend.Clitcpka,
+		"contstats":               frontend.Contstats,
+		"default_backend":         frontend.DefaultBackend,
+		"dontlognull":             frontend.Dontlognull,
+		"forwardfor":              flattenForwardfor(frontend.Forwardfor),
+		"http_buffer_request":     frontend.HttpBufferRequest,
+		"http_use_htx":            frontend.HttpUseHtx,
+		"http_connection_mode":    frontend.HttpConnectionMode,
+		"http_keep_alive_timeout": frontend.HttpKeepAliveTimeout,
+		"http_request_timeout":    frontend.HttpRequestTimeout,
+		"httplog":                 frontend.HttpLog,
+		"log_format":              frontend.LogFormat,
+		"log_format_sd":           frontend.LogFormatSd,
+		"log_separate_errors":     frontend.LogSeparateErrors,
+		"log_tag":                 frontend.LogTag,
+		"logasap":                 frontend.Logasap,
+		"maxconn":                 frontend.MaxConn,
+		"mode":                    frontend.Mode,
+		"monitor_fail":            flattenMonitorFail(frontend.MonitorFail),
+		"monitor_uri":             frontend.MonitorUri,
+		"stats_options":           flattenStatsOptions(frontend.StatsOptions),
+		"tcplog":                  frontend.TcpLog,
+		"unique_id_format":        frontend.UniqueIdFormat,
+		"unique_id_header":        frontend.UniqueIdHeader,
+	}
+
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("cannot set %s: %w", key, err)
+		}
 	}
+	return nil
+}
 
-	if v, ok := d.GetOk("tcplog"); ok {
-		frontend.TcpLog = v.(bool)
+func flattenMonitorFail(monitorFail *models.MonitorFail) []interface{} {
+	if monitorFail == nil {
+		return nil
 	}
-
-	if v, ok := d.GetOk("unique_id_format"); ok {
-		frontend.UniqueIdFormat = v.(string)
+	return []interface{}{
+		map[string]interface{}{
+			"cond":      monitorFail.Cond,
+			"cond_test": monitorFail.CondTest,
+		},
 	}
+}
 
-	if v, ok := d.GetOk("unique_id_header"); ok {
-		frontend.UniqueIdHeader = v.(string)
+func flattenStatsOptions(statsOptions *models.StatsOptions) []interface{} {
+	if statsOptions == nil {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"stats_enable":         statsOptions.StatsEnable,
+			"stats_hide_version":   statsOptions.StatsHideVersion,
+			"stats_maxconn":        statsOptions.StatsMaxconn,
+			"stats_refresh_delay":  statsOptions.StatsRefreshDelay,
+			"stats_show_desc":      statsOptions.StatsShowDesc,
+			"stats_show_legends":   statsOptions.StatsShowLegends,
+			"stats_show_node_name": statsOptions.StatsShowNodeName,
+			"stats_uri_prefix":     statsOptions.StatsUrilPrefix,
+		},
 	}
-
-	return frontend
 }
@@ -1,11 +1,21 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
 )
 
 func TestResourceFrontend(t *testing.T) {
@@ -27,6 +37,25 @@ func TestResourceFrontend(t *testing.T) {
 				),
 			},
 			importStep("haproxy_frontend.test"),
+			{
+				Config: testAccFrontendFullConfig("tfacc-frontend1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_frontend.test", "mode", "http"),
+					resource.TestCheckResourceAttr("haproxy_frontend.test", "forwardfor.#", "1"),
+					resource.TestCheckResourceAttr("haproxy_frontend.test", "monitor_fail.#", "1"),
+					resource.TestCheckResourceAttr("haproxy_frontend.test", "stats_options.#", "1"),
+					resource.TestCheckResourceAttr("haproxy_frontend.test", "unique_id_header", "X-Unique-ID"),
+				),
+			},
+			importStep("haproxy_frontend.test"),
+			{
+				Config: testAccFrontendTcpConfig("tfacc-frontend1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_frontend.test", "tcplog", "true"),
+					resource.TestCheckResourceAttr("haproxy_frontend.test", "forwardfor.#", "0"),
+				),
+			},
+			importStep("haproxy_frontend.test"),
 			{
 				ResourceName: "haproxy_frontend.test",
 				ImportStateIdFunc: func(*terraform.State) (string, error) {
@@ -46,3 +75,264 @@ resource "haproxy_frontend" "test" {
 }	
 `, name)
 }
+
+func testAccFrontendFullConfig(name string) string {
+	return fmt.Sprintf(`
+resource "haproxy_frontend" "test" {
+	name                    = "%[1]s"
+	mode                    = "http"
+	client_timeout          = 30000
+	clitcpka                = "enabled"
+	contstats               = "enabled"
+	dontlognull             = "enabled"
+	http_buffer_request     = "enabled"
+	http_connection_mode    = "http-keep-alive"
+	http_keep_alive_timeout = 5000
+	http_request_timeout    = 10000
+	httplog                 = true
+	clflog                  = true
+	log_separate_errors     = "enabled"
+	log_tag                 = "tfacc"
+	logasap                 = "enabled"
+	maxconn                 = 1000
+	monitor_uri             = "/health"
+	unique_id_format        = "%%{+X}o%%ci%%cp%%Ts"
+	unique_id_header        = "X-Unique-ID"
+
+	forwardfor {
+		enabled = "enabled"
+		except  = "127.0.0.0/8"
+		header  = "X-Client-IP"
+		ifnone  = true
+	}
+
+	monitor_fail {
+		cond      = "if"
+		cond_test = "FALSE"
+	}
+
+	stats_options {
+		stats_enable        = true
+		stats_hide_version  = true
+		stats_refresh_delay = 10
+		stats_show_legends  = true
+		stats_uri_prefix    = "/stats"
+	}
+}
+`, name)
+}
+
+func testAccFrontendTcpConfig(name string) string {
+	return fmt.Sprintf(`
+resource "haproxy_frontend" "test" {
+	name   = "%[1]s"
+	mode   = "tcp"
+	tcplog = true
+}
+`, name)
+}
+
+// TestFrontendRoundTrip checks every attribute of a frontend survives being
+// read from HAProxy then sent back, as on import.
+func TestFrontendRoundTrip(t *testing.T) {
+	frontend := &models.Frontend{
+		BindProcess:          "1",
+		Clflog:               true,
+		ClientTimeout:        30000,
+		Clitcpka:             "enabled",
+		Contstats:            "enabled",
+		DefaultBackend:       "app",
+		Dontlognull:          "enabled",
+		Forwardfor:           &models.Forwardfor{Enabled: "enabled", Except: "127.0.0.0/8", Header: "X-Client-IP", Ifnone: true},
+		HttpBufferRequest:    "enabled",
+		HttpUseHtx:           "enabled",
+		HttpConnectionMode:   "http-keep-alive",
+		HttpKeepAliveTimeout: 5000,
+		HttpRequestTimeout:   10000,
+		HttpLog:              true,
+		LogFormat:            "%ci:%cp [%tr] %ft",
+		LogFormatSd:          "[exampleSDID@1234]",
+		LogSeparateErrors:    "enabled",
+		LogTag:               "frontend",
+		Logasap:              "enabled",
+		MaxConn:              1000,
+		Mode:                 "http",
+		MonitorFail:          &models.MonitorFail{Cond: "if", CondTest: "site_dead"},
+		MonitorUri:           "/health",
+		Name:                 "public",
+		StatsOptions: &models.StatsOptions{
+			StatsEnable:       true,
+			StatsHideVersion:  true,
+			StatsMaxconn:      10,
+			StatsRefreshDelay: 5,
+			StatsShowDesc:     "public frontend",
+			StatsShowLegends:  true,
+			StatsShowNodeName: "lb1",
+			StatsUrilPrefix:   "/stats",
+		},
+		TcpLog:         true,
+		UniqueIdFormat: "%{+X}o%ci%cp",
+		UniqueIdHeader: "X-Unique-ID",
+	}
+	assertNoZeroField(t, "Frontend", reflect.ValueOf(frontend).Elem())
+
+	resourceSchema := resourceFrontend().Schema
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+	if err := flattenFrontend(d, frontend); err != nil {
+		t.Fatal(err)
+	}
+
+	for key := range resourceSchema {
+		if _, ok := d.GetOk(key); !ok {
+			t.Errorf("attribute %s is not read back", key)
+		}
+	}
+
+	if result := buildFrontendFromResourceParameters(d); !reflect.DeepEqual(result, frontend) {
+		t.Errorf("got %+v, want %+v", result, frontend)
+	}
+}
+
+// assertNoZeroField fails if a field of v, or of the structures it points to,
+// is left to its zero value, so a field added to the model must be covered.
+func assertNoZeroField(t *testing.T, name string, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		fieldName := name + "." + v.Type().Field(i).Name
+		if field.IsZero() {
+			t.Errorf("%s is not set", fieldName)
+			continue
+		}
+		if field.Kind() == reflect.Ptr && field.Elem().Kind() == reflect.Struct {
+			assertNoZeroField(t, fieldName, field.Elem())
+		}
+	}
+}
+
+// fakeFrontendAPI serves the frontends of a Data Plane API v2, applying
+// changes immediately whatever their transaction.
+type fakeFrontendAPI struct {
+	mu        sync.Mutex
+	frontends map[string]models.Frontend
+}
+
+func (f *fakeFrontendAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	name := strings.TrimPrefix(r.URL.Path, "/v2/services/haproxy/configuration/frontends/")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/configuration/raw"):
+		w.Write([]byte(`{"_version": 1, "data": ""}`))
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/transactions"):
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"_version": 1, "id": "tx-1", "status": "in_progress"}`))
+	case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/transactions/tx-1"):
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"_version": 2, "id": "tx-1", "status": "success"}`))
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/configuration/frontends"):
+		frontend := models.Frontend{}
+		json.NewDecoder(r.Body).Decode(&frontend)
+		f.frontends[frontend.Name] = frontend
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(frontend)
+	case r.Method == "GET" && name != r.URL.Path:
+		frontend, ok := f.frontends[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "frontend not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"_version": 1, "data": frontend})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeFrontendAPI) delete(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.frontends, name)
+}
+
+func newFakeFrontendClient(t *testing.T, api *fakeFrontendAPI) *haproxy.Client {
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client, err := haproxy.NewClient("admin", "adminpwd", server.URL, true, haproxy.APIVersionV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.TransactionBatchWindow = 0
+	return client
+}
+
+// TestFrontendDeletedOutOfBand checks a frontend removed by hand is dropped
+// from the state on refresh, so the next plan creates it again.
+func TestFrontendDeletedOutOfBand(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeFrontendAPI{frontends: map[string]models.Frontend{}}
+	client := newFakeFrontendClient(t, api)
+
+	r := resourceFrontend()
+	raw := map[string]interface{}{
+		"name": "public",
+		"mode": "http",
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	if diags := r.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	state := d.State()
+
+	api.delete("public")
+
+	refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, client)
+	if diags.HasError() {
+		t.Fatalf("refresh: %v", diags)
+	}
+	if refreshed != nil {
+		t.Fatalf("frontend deleted out-of-band is still in state: %v", refreshed)
+	}
+
+	diff, err := r.SimpleDiff(ctx, refreshed, terraform.NewResourceConfigRaw(raw), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.Destroy {
+		t.Fatalf("expected a create plan, got %v", diff)
+	}
+	for key, want := range raw {
+		attribute, ok := diff.Attributes[key]
+		if !ok || attribute.Old != "" || attribute.New != want {
+			t.Errorf("%s: expected to be created with %v, got %+v", key, want, attribute)
+		}
+	}
+
+	if diags := r.CreateContext(ctx, schema.TestResourceDataRaw(t, r.Schema, raw), client); diags.HasError() {
+		t.Fatalf("re-create: %v", diags)
+	}
+}
+
+// TestFrontendImportDeletedOutOfBand checks importing a missing frontend
+// leaves nothing in state rather than failing the refresh.
+func TestFrontendImportDeletedOutOfBand(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeFrontendClient(t, &fakeFrontendAPI{frontends: map[string]models.Frontend{}})
+
+	r := resourceFrontend()
+	d := r.Data(&terraform.InstanceState{ID: "public"})
+	imported, err := r.Importer.StateContext(ctx, d, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, diags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), client)
+	if diags.HasError() {
+		t.Fatalf("refresh: %v", diags)
+	}
+	if refreshed != nil {
+		t.Fatalf("missing frontend imported in state: %v", refreshed)
+	}
+}
@@ -0,0 +1,355 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// httpRuleList describes one of the http rule lists owned as a whole by a
+// resource: the http-request or the http-response rules of a section.
+type httpRuleList struct {
+	keyword string
+	types   []string
+	get     func(client *haproxy.Client, ctx context.Context, transactionId string, parentType string, parentName string) ([]models.HttpRule, error)
+	replace func(client *haproxy.Client, ctx context.Context, transactionId string, parentType string, parentName string, rules []models.HttpRule) error
+}
+
+var httpRequestRuleList = httpRuleList{
+	keyword: "http-request",
+	types: []string{
+		"allow", "deny", "redirect", "set-header", "add-header", "del-header", "replace-header",
+		"set-var", "unset-var", "return", "use-service", "lua", "set-map", "del-map",
+		"set-path", "set-query", "set-uri",
+	},
+	get:     (*haproxy.Client).GetHttpRequestRules,
+	replace: (*haproxy.Client).ReplaceHttpRequestRules,
+}
+
+var httpResponseRuleList = httpRuleList{
+	keyword: "http-response",
+	types: []string{
+		"allow", "deny", "redirect", "set-header", "add-header", "del-header", "replace-header",
+		"set-var", "unset-var", "return", "lua", "set-map", "del-map", "set-status",
+	},
+	get:     (*haproxy.Client).GetHttpResponseRules,
+	replace: (*haproxy.Client).ReplaceHttpResponseRules,
+}
+
+func resourceHttpRequestRules() *schema.Resource {
+	return httpRequestRuleList.resource("`haproxy_http_request_rules` manage the complete ordered list of http-request rules of a frontend or a backend.")
+}
+
+func resourceHttpResponseRules() *schema.Resource {
+	return httpResponseRuleList.resource("`haproxy_http_response_rules` manage the complete ordered list of http-response rules of a frontend or a backend.")
+}
+
+func (l httpRuleList) resource(description string) *schema.Resource {
+	return &schema.Resource{
+		Description:   description,
+		CreateContext: l.create,
+		ReadContext:   l.read,
+		UpdateContext: l.update,
+		DeleteContext: l.delete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importRuleList,
+		},
+		Timeouts: defaultTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"parent_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the frontend or backend the rules belong to.",
+			},
+			"parent_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Type of the section the rules belong to. Possible value : 'frontend' or 'backend'.",
+				ValidateFunc: validation.StringInSlice([]string{"frontend", "backend"}, false),
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: fmt.Sprintf("Ordered list of %s rules. Rules not declared here are removed. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-%s", l.keyword, l.keyword),
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  fmt.Sprintf("Rule action. Possible value : '%s'.", joinValues(l.types)),
+							ValidateFunc: validation.StringInSlice(l.types, false),
+						},
+						"cond": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Condition type. Possible value : 'if' or 'unless'.",
+							ValidateFunc: validation.StringInSlice([]string{"if", "unless"}, false),
+						},
+						"cond_test": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ACL condition, e.g. 'is_test_ok' or '!{ src 10.0.0.0/8 }'.",
+						},
+						"deny_status": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Status code returned by the 'deny' action.",
+						},
+						"redir_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Redirect type of the 'redirect' action. Possible value : 'location', 'prefix' or 'scheme'.",
+							ValidateFunc: validation.StringInSlice([]string{"location", "prefix", "scheme"}, false),
+						},
+						"redir_value": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Location, prefix or scheme of the 'redirect' action.",
+						},
+						"redir_code": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "Status code of the 'redirect' action. Possible value : 301, 302, 303, 307 or 308.",
+							ValidateFunc: validation.IntInSlice([]int{301, 302, 303, 307, 308}),
+						},
+						"redir_option": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Options of the 'redirect' action, e.g. 'drop-query'.",
+						},
+						"hdr_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Header name of the 'set-header', 'add-header', 'del-header' and 'replace-header' actions.",
+						},
+						"hdr_format": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Log-format string used as header value.",
+						},
+						"hdr_match": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Regular expression matched by the 'replace-header' action.",
+						},
+						"var_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Variable name of the 'set-var' and 'unset-var' actions.",
+						},
+						"var_scope": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Variable scope, e.g. 'txn', 'req' or 'sess'.",
+						},
+						"var_expr": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Sample expression assigned by the 'set-var' action.",
+						},
+						"return_status_code": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Status code of the 'return' action.",
+						},
+						"return_content_type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Content type of the 'return' action.",
+						},
+						"return_content_format": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Content format of the 'return' action. Possible value : 'default-errorfile', 'errorfile', 'errorfiles', 'file', 'lf-file', 'string' or 'lf-string'.",
+							ValidateFunc: validation.StringInSlice([]string{"default-errorfile", "errorfile", "errorfiles", "file", "lf-file", "string", "lf-string"}, false),
+						},
+						"return_content": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Content of the 'return' action.",
+						},
+						"service_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Service of the 'use-service' action, e.g. 'prometheus-exporter'.",
+						},
+						"lua_action": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Lua action registered with core.register_action, used by the 'lua' action.",
+						},
+						"lua_params": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Parameters passed to the lua action.",
+						},
+						"map_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Map file of the 'set-map' and 'del-map' actions, e.g. '/etc/haproxy/maps/test.map'.",
+						},
+						"map_keyfmt": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Log-format string used as map key.",
+						},
+						"map_valuefmt": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Log-format string used as map value by the 'set-map' action.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// importRuleList accepts <frontend|backend>/<parentName> for every resource
+// owning a whole rule list.
+func importRuleList(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	idMatchFormat, _ := regexp.MatchString("^(frontend|backend)/(.+)$", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected <frontend|backend>/<parentName>, e.g. frontend/my-frontend, actual id is %s", d.Id())
+	}
+
+	d.Set("parent_type", haproxy.ExtractStringWithRegex(d.Id(), "^(frontend|backend)/"))
+	d.Set("parent_name", haproxy.ExtractStringWithRegex(d.Id(), "^(?:frontend|backend)/(.+)$"))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func (l httpRuleList) read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	rules, err := l.get(client, ctx, "", d.Get("parent_type").(string), d.Get("parent_name").(string))
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.Set("rule", flattenHttpRules(rules))
+
+	return nil
+}
+
+func (l httpRuleList) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := l.apply(ctx, d, meta, buildHttpRulesFromResourceParameters(d))
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(d.Get("parent_type").(string) + "/" + d.Get("parent_name").(string))
+	return l.read(ctx, d, meta)
+}
+
+func (l httpRuleList) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := l.apply(ctx, d, meta, buildHttpRulesFromResourceParameters(d))
+	if diags.HasError() {
+		return diags
+	}
+	return l.read(ctx, d, meta)
+}
+
+func (l httpRuleList) delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := l.apply(ctx, d, meta, nil)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId("")
+	return nil
+}
+
+func (l httpRuleList) apply(ctx context.Context, d *schema.ResourceData, meta interface{}, rules []models.HttpRule) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		return l.replace(client, ctx, transactionId, parentType, parentName, rules)
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	return nil
+}
+
+func buildHttpRulesFromResourceParameters(d *schema.ResourceData) []models.HttpRule {
+	items := d.Get("rule").([]interface{})
+	rules := make([]models.HttpRule, 0, len(items))
+	for _, item := range items {
+		v := item.(map[string]interface{})
+		rules = append(rules, models.HttpRule{
+			Type:                v["type"].(string),
+			Cond:                v["cond"].(string),
+			CondTest:            v["cond_test"].(string),
+			DenyStatus:          v["deny_status"].(int),
+			RedirType:           v["redir_type"].(string),
+			RedirValue:          v["redir_value"].(string),
+			RedirCode:           v["redir_code"].(int),
+			RedirOption:         v["redir_option"].(string),
+			HdrName:             v["hdr_name"].(string),
+			HdrFormat:           v["hdr_format"].(string),
+			HdrMatch:            v["hdr_match"].(string),
+			VarName:             v["var_name"].(string),
+			VarScope:            v["var_scope"].(string),
+			VarExpr:             v["var_expr"].(string),
+			ReturnStatusCode:    v["return_status_code"].(int),
+			ReturnContentType:   v["return_content_type"].(string),
+			ReturnContentFormat: v["return_content_format"].(string),
+			ReturnContent:       v["return_content"].(string),
+			ServiceName:         v["service_name"].(string),
+			LuaAction:           v["lua_action"].(string),
+			LuaParams:           v["lua_params"].(string),
+			MapFile:             v["map_file"].(string),
+			MapKeyfmt:           v["map_keyfmt"].(string),
+			MapValuefmt:         v["map_valuefmt"].(string),
+		})
+	}
+	return rules
+}
+
+func flattenHttpRules(rules []models.HttpRule) []interface{} {
+	items := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, map[string]interface{}{
+			"type":                  rule.Type,
+			"cond":                  rule.Cond,
+			"cond_test":             rule.CondTest,
+			"deny_status":           rule.DenyStatus,
+			"redir_type":            rule.RedirType,
+			"redir_value":           rule.RedirValue,
+			"redir_code":            rule.RedirCode,
+			"redir_option":          rule.RedirOption,
+			"hdr_name":              rule.HdrName,
+			"hdr_format":            rule.HdrFormat,
+			"hdr_match":             rule.HdrMatch,
+			"var_name":              rule.VarName,
+			"var_scope":             rule.VarScope,
+			"var_expr":              rule.VarExpr,
+			"return_status_code":    rule.ReturnStatusCode,
+			"return_content_type":   rule.ReturnContentType,
+			"return_content_format": rule.ReturnContentFormat,
+			"return_content":        rule.ReturnContent,
+			"service_name":          rule.ServiceName,
+			"lua_action":            rule.LuaAction,
+			"lua_params":            rule.LuaParams,
+			"map_file":              rule.MapFile,
+			"map_keyfmt":            rule.MapKeyfmt,
+			"map_valuefmt":          rule.MapValuefmt,
+		})
+	}
+	return items
+}
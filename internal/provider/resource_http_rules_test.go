@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceHttpRequestRules(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHttpRequestRulesConfig("tfacc-http-rules", "X-Tfacc"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_http_request_rules.test", "id", "frontend/tfacc-http-rules"),
+					resource.TestCheckResourceAttr("haproxy_http_request_rules.test", "rule.#", "3"),
+					resource.TestCheckResourceAttr("haproxy_http_request_rules.test", "rule.0.type", "set-header"),
+					resource.TestCheckResourceAttr("haproxy_http_request_rules.test", "rule.2.type", "deny"),
+				),
+			},
+			importStep("haproxy_http_request_rules.test"),
+			{
+				Config: testAccHttpRequestRulesConfig("tfacc-http-rules", "X-Tfacc-Updated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_http_request_rules.test", "rule.0.hdr_name", "X-Tfacc-Updated"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceHttpResponseRules(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHttpResponseRulesConfig("tfacc-http-response-rules"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_http_response_rules.test", "rule.#", "2"),
+				),
+			},
+			importStep("haproxy_http_response_rules.test"),
+		},
+	})
+}
+
+func testAccHttpRequestRulesConfig(frontendName string, header string) string {
+	return fmt.Sprintf(`
+resource "haproxy_frontend" "test" {
+	name = "%[1]s"
+}
+
+resource "haproxy_http_request_rules" "test" {
+	parent_type = "frontend"
+	parent_name = haproxy_frontend.test.name
+
+	rule {
+		type       = "set-header"
+		hdr_name   = "%[2]s"
+		hdr_format = "%%[src]"
+	}
+
+	rule {
+		type         = "set-map"
+		map_file     = "/etc/haproxy/maps/test.map"
+		map_keyfmt   = "%%[src]"
+		map_valuefmt = "seen"
+	}
+
+	rule {
+		type        = "deny"
+		deny_status = 403
+		cond        = "if"
+		cond_test   = "{ path_beg /admin }"
+	}
+}
+`, frontendName, header)
+}
+
+func testAccHttpResponseRulesConfig(backendName string) string {
+	return fmt.Sprintf(`
+resource "haproxy_backend" "test" {
+	name = "%[1]s"
+	mode = "http"
+}
+
+resource "haproxy_http_response_rules" "test" {
+	parent_type = "backend"
+	parent_name = haproxy_backend.test.name
+
+	rule {
+		type     = "del-header"
+		hdr_name = "Server"
+	}
+
+	rule {
+		type       = "set-header"
+		hdr_name   = "Strict-Transport-Security"
+		hdr_format = "max-age=31536000"
+	}
+}
+`, backendName)
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func resourceMap() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_map` manage the entries of a map as a whole. Only the entries differing from the runtime map are changed.",
+		CreateContext: resourceMapCreate,
+		ReadContext:   resourceMapRead,
+		UpdateContext: resourceMapUpdate,
+		DeleteContext: resourceMapDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceMapImport,
+		},
+		Timeouts: defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The HAProxy map name. More informations : https://www.haproxy.com/fr/blog/introduction-to-haproxy-maps/",
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					return validation.StringIsNotWhiteSpace(i, s)
+				},
+			},
+			"entries": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Description:  "Entries of the map, by key. Blanks are not allowed in keys.",
+				ValidateFunc: validateMapEntries,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"remove_unmanaged": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, the entries of the map not declared in 'entries' are deleted. Otherwise they are left alone. Default value false",
+			},
+			"force_sync": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If true, syncs changes to disk once all of them are made",
+			},
+			"bulk_load": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, new entries are added in bulk with the runtime 'add map' payload instead of one request per entry, which is much faster for large maps. Requires HAProxy 2.4 or later. Changed and deleted entries still take one request each. Default value false",
+			},
+			"bulk_chunk_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				Description:  "Maximum number of entries added per request with 'bulk_load'. Default value 1000",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+		},
+	}
+}
+
+// resourceMapImport imports every current entry of the map, given as
+// map/<mapName>.
+func resourceMapImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*haproxy.Client)
+
+	idMatchFormat, _ := regexp.MatchString("^map/.+$", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected map/<mapName>, e.g. map/ratelimit, actual id is %s", d.Id())
+	}
+
+	mapName := strings.TrimPrefix(d.Id(), "map/")
+	mapEntries, err := client.GetMapEntries(ctx, mapName)
+	if err != nil {
+		return nil, fmt.Errorf("error on getting map entries during import: %s", err)
+	}
+
+	entries := map[string]interface{}{}
+	for _, entrie := range mapEntries {
+		entries[entrie.Key] = entrie.Value
+	}
+
+	d.SetId(mapName)
+	d.Set("name", mapName)
+	d.Set("entries", entries)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceMapCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	mapName := d.Get("name").(string)
+
+	err := client.Retry(ctx, func() error {
+		return client.SyncMapEntries(ctx, mapName, expandMapEntries(d.Get("entries")), mapSyncOptions(d, nil))
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId(mapName)
+
+	return resourceMapRead(ctx, d, meta)
+}
+
+func resourceMapRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	mapEntries, err := client.GetMapEntries(ctx, d.Id())
+
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	// without remove_unmanaged, entries not declared are not part of the
+	// resource and don't show up as a diff
+	managed := expandMapEntries(d.Get("entries"))
+	removeUnmanaged := d.Get("remove_unmanaged").(bool)
+
+	entries := map[string]interface{}{}
+	for _, entrie := range mapEntries {
+		if _, ok := managed[entrie.Key]; ok || removeUnmanaged {
+			entries[entrie.Key] = entrie.Value
+		}
+	}
+
+	d.Set("name", d.Id())
+	d.Set("entries", entries)
+	d.Set("remove_unmanaged", removeUnmanaged)
+	d.Set("force_sync", d.Get("force_sync").(bool))
+	return nil
+}
+
+func resourceMapUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	if d.HasChanges("entries", "remove_unmanaged") {
+		old, _ := d.GetChange("entries")
+		stale := []string{}
+		for key := range expandMapEntries(old) {
+			stale = append(stale, key)
+		}
+
+		err := client.Retry(ctx, func() error {
+			return client.SyncMapEntries(ctx, d.Id(), expandMapEntries(d.Get("entries")), mapSyncOptions(d, stale))
+		})
+		if err != nil {
+			return apiDiagnostics(d, err)
+		}
+	}
+
+	return resourceMapRead(ctx, d, meta)
+}
+
+func resourceMapDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	managed := []string{}
+	for key := range expandMapEntries(d.Get("entries")) {
+		managed = append(managed, key)
+	}
+
+	err := client.Retry(ctx, func() error {
+		return client.SyncMapEntries(ctx, d.Id(), map[string]string{}, haproxy.MapSyncOptions{
+			Stale:     managed,
+			ForceSync: d.Get("force_sync").(bool),
+		})
+	})
+	if err != nil && !errors.Is(err, haproxy.ErrNotFound) {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// validateMapEntries checks that HAProxy can store every entry of a map.
+func validateMapEntries(i interface{}, s string) ([]string, []error) {
+	var errs []error
+	for key, value := range i.(map[string]interface{}) {
+		if err := haproxy.ValidateMapEntrie(models.MapEntrie{Key: key, Value: value.(string)}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return nil, errs
+}
+
+// mapSyncOptions returns the options syncing the configured entries, stale
+// being the keys managed previously.
+func mapSyncOptions(d *schema.ResourceData, stale []string) haproxy.MapSyncOptions {
+	options := haproxy.MapSyncOptions{
+		Stale:           stale,
+		RemoveUnmanaged: d.Get("remove_unmanaged").(bool),
+		ForceSync:       d.Get("force_sync").(bool),
+	}
+	if d.Get("bulk_load").(bool) {
+		options.BulkChunkSize = d.Get("bulk_chunk_size").(int)
+	}
+	return options
+}
+
+func expandMapEntries(entries interface{}) map[string]string {
+	res := map[string]string{}
+	for key, value := range entries.(map[string]interface{}) {
+		res[key] = value.(string)
+	}
+	return res
+}
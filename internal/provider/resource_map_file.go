@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+func resourceMapFile() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_map_file` manage map files in the Dataplane API storage.",
+		CreateContext: resourceMapFileCreate,
+		ReadContext:   resourceMapFileRead,
+		UpdateContext: resourceMapFileUpdate,
+		DeleteContext: resourceMapFileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if d.HasChange("entries") {
+				return d.SetNewComputed("content")
+			}
+			return nil
+		},
+		Timeouts: defaultTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the map file in the storage, e.g. 'ratelimit.map'",
+				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+					return validation.StringIsNotWhiteSpace(i, s)
+				},
+			},
+			"content": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Description:   "Content of the map file, one 'key value' line per entry",
+				ConflictsWith: []string{"entries"},
+			},
+			"entries": {
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Description:   "Entries of the map file, by key. The content is rendered with one line per entry sorted by key. Blanks are not allowed in keys.",
+				ConflictsWith: []string{"content"},
+				ValidateFunc:  validateMapEntries,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Path of the map file on the HAProxy host, to use in ACL and rule expressions such as 'map(/etc/haproxy/maps/ratelimit.map)'",
+			},
+			"runtime_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the map loaded by HAProxy, empty until the configuration references the map file",
+			},
+		},
+	}
+}
+
+func resourceMapFileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	name := d.Get("name").(string)
+
+	content := mapFileContent(d)
+	sent := false
+	err := client.Retry(ctx, func() error {
+		_, err := client.CreateMapFile(ctx, name, content)
+		if errors.Is(err, haproxy.ErrConflict) {
+			if !sent {
+				return fmt.Errorf("map file %s already exists, import it", name)
+			}
+			// an earlier attempt was stored despite failing
+			return client.ReplaceMapFile(ctx, name, content)
+		}
+		sent = true
+		return err
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId(name)
+
+	return resourceMapFileRead(ctx, d, meta)
+}
+
+func resourceMapFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	mapFile, err := client.GetMapFile(ctx, d.Id())
+
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	content, err := client.GetMapFileContent(ctx, d.Id())
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	runtimeId := ""
+	runtimeMap, err := client.GetRuntimeMap(ctx, strings.TrimSuffix(d.Id(), path.Ext(d.Id())))
+	if err != nil && !errors.Is(err, haproxy.ErrNotFound) {
+		return apiDiagnostics(d, err)
+	}
+	if err == nil {
+		runtimeId = runtimeMap.Id
+	}
+
+	d.Set("name", d.Id())
+	d.Set("content", content)
+	if len(d.Get("entries").(map[string]interface{})) > 0 {
+		d.Set("entries", haproxy.ParseMapFile(content))
+	}
+	d.Set("path", mapFile.File)
+	d.Set("runtime_id", runtimeId)
+	return nil
+}
+
+func resourceMapFileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	if d.HasChanges("content", "entries") {
+		content := mapFileContent(d)
+		err := client.Retry(ctx, func() error {
+			return client.ReplaceMapFile(ctx, d.Id(), content)
+		})
+		if err != nil {
+			return apiDiagnostics(d, err)
+		}
+	}
+
+	return resourceMapFileRead(ctx, d, meta)
+}
+
+func resourceMapFileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	err := client.Retry(ctx, func() error {
+		return client.DeleteMapFile(ctx, d.Id())
+	})
+	if err != nil && !errors.Is(err, haproxy.ErrNotFound) {
+		return apiDiagnostics(d, err)
+	}
+	d.SetId("")
+	return nil
+}
+
+// mapFileContent returns the configured content of the map file, rendered
+// from entries when they are used.
+func mapFileContent(d *schema.ResourceData) string {
+	if entries := d.Get("entries").(map[string]interface{}); len(entries) > 0 {
+		return haproxy.FormatMapFile(expandMapEntries(entries))
+	}
+	return d.Get("content").(string)
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func TestResourceMapFile(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMapFileEntries("acc-test.map", `
+		"/api"     = "200"
+		"/metrics" = "50"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_map_file.test", "name", "acc-test.map"),
+					resource.TestCheckResourceAttr("haproxy_map_file.test", "content", "/api 200\n/metrics 50\n"),
+					resource.TestCheckResourceAttrSet("haproxy_map_file.test", "path"),
+				),
+			},
+			{
+				Config: testAccMapFileContent("acc-test.map", `/api 100\n`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_map_file.test", "content", "/api 100\n"),
+				),
+			},
+			importStep("haproxy_map_file.test"),
+		},
+	})
+}
+
+func testAccMapFileEntries(name string, entries string) string {
+	return fmt.Sprintf(`
+resource "haproxy_map_file" "test" {
+	name    = "%s"
+	entries = {%s
+	}
+}
+`, name, entries)
+}
+
+func testAccMapFileContent(name string, content string) string {
+	return fmt.Sprintf(`
+resource "haproxy_map_file" "test" {
+	name    = "%s"
+	content = "%s"
+}
+`, name, content)
+}
+
+// fakeMapStorageAPI serves the map files of the storage. The first upload is
+// stored but answered with an error, as when the response is lost.
+type fakeMapStorageAPI struct {
+	mu       sync.Mutex
+	files    map[string]string
+	uploads  int
+	replaces int
+}
+
+func (f *fakeMapStorageAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	name := strings.TrimPrefix(r.URL.Path, "/v2/services/haproxy/storage/maps/")
+	switch {
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/storage/maps"):
+		file, header, err := r.FormFile("file_upload")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		if _, ok := f.files[header.Filename]; ok {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"code": 409, "message": "file already exists"}`))
+			return
+		}
+		f.files[header.Filename] = string(content)
+		f.uploads++
+		if f.uploads == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.MapFile{StorageName: header.Filename})
+	case r.Method == "PUT" && name != r.URL.Path:
+		content, _ := io.ReadAll(r.Body)
+		f.files[name] = string(content)
+		f.replaces++
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/storage/maps"):
+		mapFiles := []models.MapFile{}
+		for name := range f.files {
+			mapFiles = append(mapFiles, models.MapFile{StorageName: name, File: "/etc/haproxy/maps/" + name})
+		}
+		json.NewEncoder(w).Encode(mapFiles)
+	case r.Method == "GET" && name != r.URL.Path:
+		w.Write([]byte(f.files[name]))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestMapFileCreateRetriesIdempotently(t *testing.T) {
+	api := &fakeMapStorageAPI{files: map[string]string{}}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client, err := haproxy.NewClient("admin", "adminpwd", server.URL, true, haproxy.APIVersionV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.RetryMaxWait = time.Millisecond
+
+	r := resourceMapFile()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":    "retry.map",
+		"content": "/api 200\n",
+	})
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if api.uploads != 1 || api.replaces != 1 {
+		t.Fatalf("expected the conflicting retry to replace the file, got %d uploads and %d replaces", api.uploads, api.replaces)
+	}
+	if d.Id() != "retry.map" || d.Get("content").(string) != "/api 200\n" {
+		t.Fatalf("unexpected state %s: %q", d.Id(), d.Get("content"))
+	}
+}
+
+func TestMapFileCreateDoesNotOverwriteExistingFile(t *testing.T) {
+	api := &fakeMapStorageAPI{files: map[string]string{"test.map": "/existing 1\n"}, uploads: 1}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client, err := haproxy.NewClient("admin", "adminpwd", server.URL, true, haproxy.APIVersionV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.RetryMaxWait = time.Millisecond
+
+	r := resourceMapFile()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":    "test.map",
+		"content": "/api 200\n",
+	})
+	diags := r.CreateContext(context.Background(), d, client)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "map file test.map already exists, import it") {
+		t.Fatalf("expected the existing map file to be reported, got %v", diags)
+	}
+	if api.replaces != 0 || api.files["test.map"] != "/existing 1\n" {
+		t.Fatalf("expected the existing map file to be left alone, got %d replaces and %q", api.replaces, api.files["test.map"])
+	}
+	if d.Id() != "" {
+		t.Fatalf("expected nothing in state, got %s", d.Id())
+	}
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceMap(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMap("resource_map", `
+		"/map/a" = "10"
+		"/map/b" = "20"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_map.test", "name", "resource_map"),
+					resource.TestCheckResourceAttr("haproxy_map.test", "entries.%", "2"),
+					resource.TestCheckResourceAttr("haproxy_map.test", "entries./map/a", "10"),
+				),
+			},
+			{
+				Config: testAccMap("resource_map", `
+		"/map/a" = "15"
+		"/map/c" = "30"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_map.test", "entries.%", "2"),
+					resource.TestCheckResourceAttr("haproxy_map.test", "entries./map/a", "15"),
+					resource.TestCheckResourceAttr("haproxy_map.test", "entries./map/c", "30"),
+					resource.TestCheckNoResourceAttr("haproxy_map.test", "entries./map/b"),
+				),
+			},
+			{
+				Config: testAccMapBulk("resource_map", `
+		"/map/a" = "15"
+		"/map/c" = "30"
+		"/map/d" = "40"
+		"/map/e" = "50"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_map.test", "entries.%", "4"),
+					resource.TestCheckResourceAttr("haproxy_map.test", "entries./map/e", "50"),
+				),
+			},
+			{
+				ResourceName:            "haproxy_map.test",
+				ImportState:             true,
+				ImportStateId:           "map/resource_map",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_sync", "bulk_load", "bulk_chunk_size"},
+			},
+		},
+	})
+}
+
+func testAccMap(mapName string, entries string) string {
+	return fmt.Sprintf(`
+resource "haproxy_map" "test" {
+	name    = "%s"
+	entries = {%s
+	}
+}
+`, mapName, entries)
+}
+
+func testAccMapBulk(mapName string, entries string) string {
+	return fmt.Sprintf(`
+resource "haproxy_map" "test" {
+	name            = "%s"
+	bulk_load       = true
+	bulk_chunk_size = 1
+	entries = {%s
+	}
+}
+`, mapName, entries)
+}
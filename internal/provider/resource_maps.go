@@ -21,8 +21,9 @@ func resourceMaps() *schema.Resource {
 		UpdateContext: resourceMapsUpdate,
 		DeleteContext: resourceMapsDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceMapEntrieImport,
+			StateContext: resourceMapEntrieImport,
 		},
+		Timeouts: defaultTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"map": {
@@ -38,9 +39,12 @@ func resourceMaps() *schema.Resource {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "Key name",
+				Description: "Key name. Blanks are not allowed.",
 				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
-					return validation.StringIsNotWhiteSpace(i, s)
+					if err := haproxy.ValidateMapKey(i.(string)); err != nil {
+						return nil, []error{err}
+					}
+					return nil, nil
 				},
 			},
 			"value": {
@@ -62,21 +66,18 @@ func resourceMaps() *schema.Resource {
 	}
 }
 
-func resourceMapEntrieImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceMapEntrieImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*haproxy.Client)
 
-	idMatchFormat, _ := regexp.MatchString("map/(.*?)/entrie/(.*?)", d.Id())
-	if !idMatchFormat {
-		return nil, fmt.Errorf("invalid format: expected map/<mapName>/entrie/<entrieName>, e.g. map/test/entrie/my-key, actual id is %s", d.Id())
+	mapName, mapEntrie, ok := parseMapEntrieId(d.Id())
+	if !ok {
+		return nil, fmt.Errorf("invalid format: expected map/<mapName>/entrie/<entrieName>, e.g. map/test/entrie/my-key, actual id is %s. To import a whole map, import it as a haproxy_map or generate import blocks with cmd/map-import", d.Id())
 	}
 
-	mapName := haproxy.ExtractStringWithRegex(d.Id(), "map/(.*?)/")
-	mapEntrie := haproxy.ExtractStringWithRegex(d.Id(), "entrie/(.*?)$")
-
 	d.SetId(mapEntrie)
 	d.Set("map", mapName)
 
-	_, err := client.GetMapEntrie(mapEntrie, mapName)
+	_, err := client.GetMapEntrie(ctx, mapEntrie, mapName)
 	if err != nil {
 		return nil, fmt.Errorf("error on getting map entrie during import: %s", err)
 	}
@@ -84,6 +85,18 @@ func resourceMapEntrieImport(d *schema.ResourceData, meta interface{}) ([]*schem
 	return []*schema.ResourceData{d}, nil
 }
 
+var mapEntrieIdRegex = regexp.MustCompile("^map/(.+)/entrie/(.*)$")
+
+// parseMapEntrieId splits an import ID map/<mapName>/entrie/<entrieName> on
+// its last /entrie/, as map names such as storage paths may contain slashes.
+func parseMapEntrieId(id string) (string, string, bool) {
+	match := mapEntrieIdRegex.FindStringSubmatch(id)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
 func resourceMapsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*haproxy.Client)
 	mapName := d.Get("map").(string)
@@ -92,15 +105,18 @@ func resourceMapsCreate(ctx context.Context, d *schema.ResourceData, meta interf
 		Value: d.Get("value").(string),
 	}
 	forceSync := d.Get("force_sync").(bool)
-	_, err := client.CreateMapEntrie(newEntrie, mapName, forceSync)
+	_, err := client.CreateMapEntrie(ctx, newEntrie, mapName, forceSync)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiDiagnostics(d, err)
 	}
 
-	_, err = client.GetMapEntrie(newEntrie.Key, mapName)
+	// HAProxy answers successfully even when it couldn't store the entry
+	_, err = client.GetMapEntrie(ctx, newEntrie.Key, mapName)
+	if errors.Is(err, haproxy.ErrNotFound) {
+		return diag.FromErr(&haproxy.InvalidMapEntrieError{Key: newEntrie.Key, Reason: "HAProxy did not store it in map " + mapName + "."})
+	}
 	if err != nil {
-		errMessage := errors.New("Cannot insert " + newEntrie.Key + ". Space is not allowed.")
-		return diag.FromErr(errMessage)
+		return apiDiagnostics(d, err)
 	}
 
 	d.SetId(newEntrie.Key)
@@ -111,7 +127,7 @@ func resourceMapsCreate(ctx context.Context, d *schema.ResourceData, meta interf
 func resourceMapsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*haproxy.Client)
 
-	mapEntrie, err := client.GetMapEntrie(d.Id(), d.Get("map").(string))
+	mapEntrie, err := client.GetMapEntrie(ctx, d.Id(), d.Get("map").(string))
 
 	if errors.Is(err, haproxy.ErrNotFound) {
 		d.SetId("")
@@ -119,7 +135,7 @@ func resourceMapsRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	}
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiDiagnostics(d, err)
 	}
 
 	d.Set("key", mapEntrie.Key)
@@ -134,16 +150,16 @@ func resourceMapsUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	if d.HasChange("value") {
 		mapName := d.Get("map").(string)
-		entrie, err := client.GetMapEntrie(d.Get("key").(string), mapName)
+		entrie, err := client.GetMapEntrie(ctx, d.Get("key").(string), mapName)
 		if err != nil {
-			return diag.FromErr(err)
+			return apiDiagnostics(d, err)
 		}
 
 		entrie.Value = d.Get("value").(string)
 
-		_, err = client.UpdateMapEntrie(entrie, mapName, d.Get("force_sync").(bool))
+		_, err = client.UpdateMapEntrie(ctx, entrie, mapName, d.Get("force_sync").(bool))
 		if err != nil {
-			return diag.FromErr(err)
+			return apiDiagnostics(d, err)
 		}
 	}
 
@@ -154,9 +170,9 @@ func resourceMapsDelete(ctx context.Context, d *schema.ResourceData, meta interf
 	client := meta.(*haproxy.Client)
 	mapName := d.Get("map").(string)
 	forceSync := d.Get("force_sync").(bool)
-	err := client.DeleteMapEntrie(d.Id(), mapName, forceSync)
+	err := client.DeleteMapEntrie(ctx, d.Id(), mapName, forceSync)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiDiagnostics(d, err)
 	}
 	d.SetId("")
 	return nil
@@ -84,6 +84,24 @@ func TestCreateMapEntrie_with_invalid_key(t *testing.T) {
 	})
 }
 
+func TestParseMapEntrieId(t *testing.T) {
+	for id, want := range map[string][2]string{
+		"map/test/entrie/my-key":                       {"test", "my-key"},
+		"map//etc/haproxy/maps/hosts.map/entrie/a.com": {"/etc/haproxy/maps/hosts.map", "a.com"},
+		"map/test/entrie/path/to/key":                  {"test", "path/to/key"},
+	} {
+		mapName, key, ok := parseMapEntrieId(id)
+		if !ok || mapName != want[0] || key != want[1] {
+			t.Errorf("parseMapEntrieId(%q) = %q, %q, %t, want %q, %q", id, mapName, key, ok, want[0], want[1])
+		}
+	}
+	for _, id := range []string{"test/my-key", "maps/test/entrie/my-key", "map/entrie/my-key"} {
+		if _, _, ok := parseMapEntrieId(id); ok {
+			t.Errorf("parseMapEntrieId(%q) accepted an invalid id", id)
+		}
+	}
+}
+
 func testAccMapEntrie(mapName string, key string, value string, resourceName string) string {
 	return fmt.Sprintf(`
 resource "haproxy_maps" "%[4]s" {
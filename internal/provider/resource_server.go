@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func resourceServer() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`haproxy_server` manage backend servers.",
+		CreateContext: resourceServerCreate,
+		ReadContext:   resourceServerRead,
+		UpdateContext: resourceServerUpdate,
+		DeleteContext: resourceServerDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerImport,
+		},
+		Timeouts: defaultTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"backend": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the backend the server belongs to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Server name",
+			},
+			"address": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "IPv4 or IPv6 address or hostname of the server. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4-server",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Port the server listens on. If not set, the port of the incoming connection is used.",
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"weight": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "Server's weight relative to other servers. Changed through the runtime server endpoint, without reload, then persisted in the configuration. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-weight",
+				ValidateFunc: validation.IntBetween(0, 256),
+			},
+			"check": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Enable health checks on the server. Possible value : 'enabled' or 'disabled'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-check",
+				ValidateFunc: validation.StringInSlice(enabledDisabled, false),
+			},
+			"inter": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Interval in milliseconds between two consecutive health checks. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-inter",
+			},
+			"rise": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Number of consecutive successful health checks before a server is considered operational. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-rise",
+			},
+			"fall": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Number of consecutive unsuccessful health checks before a server is considered dead. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-fall",
+			},
+			"ssl": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Enable SSL ciphering on outgoing connections to the server. Possible value : 'enabled' or 'disabled'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-ssl",
+				ValidateFunc: validation.StringInSlice(enabledDisabled, false),
+			},
+			"verify": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Verification of the server certificate. Possible value : 'none' or 'required'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-verify",
+				ValidateFunc: validation.StringInSlice([]string{"none", "required"}, false),
+			},
+			"sni": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Sample expression used to set the SNI sent to the server. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-sni",
+			},
+			"maxconn": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Maximum number of concurrent connections sent to the server. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-maxconn",
+			},
+			"backup": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only use the server when all other non-backup servers are unavailable. Possible value : 'enabled' or 'disabled'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-backup",
+				ValidateFunc: validation.StringInSlice(enabledDisabled, false),
+			},
+			"send_proxy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Send a PROXY protocol header to the server. Possible value : 'enabled' or 'disabled'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-send-proxy",
+				ValidateFunc: validation.StringInSlice(enabledDisabled, false),
+			},
+			"maintenance": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Put the server in maintenance mode. Changed through the runtime server endpoint, without reload, then persisted in the configuration. Possible value : 'enabled' or 'disabled'. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#5.2-disabled",
+				ValidateFunc: validation.StringInSlice(enabledDisabled, false),
+			},
+		},
+	}
+}
+
+func resourceServerImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	idMatchFormat, _ := regexp.MatchString("^backend/(.+?)/server/(.+)$", d.Id())
+	if !idMatchFormat {
+		return nil, fmt.Errorf("invalid format: expected backend/<backendName>/server/<serverName>, e.g. backend/my-backend/server/web1, actual id is %s", d.Id())
+	}
+
+	d.Set("backend", haproxy.ExtractStringWithRegex(d.Id(), "^backend/(.+?)/server/"))
+	d.Set("name", haproxy.ExtractStringWithRegex(d.Id(), "/server/(.+)$"))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	server := models.Server{
+		Name: d.Get("name").(string),
+	}
+
+	result, err := client.GetServer(ctx, d.Get("backend").(string), server)
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	weight := 1
+	if result.Weight != nil {
+		weight = *result.Weight
+	}
+
+	d.Set("name", result.Name)
+	d.Set("address", result.Address)
+	d.Set("port", result.Port)
+	d.Set("weight", weight)
+	d.Set("check", result.Check)
+	d.Set("inter", result.Inter)
+	d.Set("rise", result.Rise)
+	d.Set("fall", result.Fall)
+	d.Set("ssl", result.Ssl)
+	d.Set("verify", result.Verify)
+	d.Set("sni", result.Sni)
+	d.Set("maxconn", result.MaxConn)
+	d.Set("backup", result.Backup)
+	d.Set("send_proxy", result.SendProxy)
+	d.Set("maintenance", result.Maintenance)
+
+	return nil
+}
+
+func resourceServerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	backendName := d.Get("backend").(string)
+	server := *buildServerFromResourceParameters(d)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		_, err := client.CreateServer(ctx, transactionId, backendName, server)
+		return err
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	d.SetId("backend/" + backendName + "/server/" + server.Name)
+	return resourceServerRead(ctx, d, meta)
+}
+
+func resourceServerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	backendName := d.Get("backend").(string)
+	server := *buildServerFromResourceParameters(d)
+
+	var err error
+	if d.HasChangesExcept("weight", "maintenance") {
+		err = withTransaction(ctx, client, func(transactionId string) error {
+			_, err := client.UpdateServer(ctx, transactionId, backendName, server)
+			return err
+		})
+	} else {
+		err = updateServerAtRuntime(ctx, d, client, backendName, server)
+	}
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	return resourceServerRead(ctx, d, meta)
+}
+
+// updateServerAtRuntime applies weight and maintenance changes without
+// reloading HAProxy: the weight and the admin state are set through the
+// runtime server endpoint, then the configuration is replaced outside of a
+// transaction so that they survive the next reload.
+func updateServerAtRuntime(ctx context.Context, d *schema.ResourceData, client *haproxy.Client, backendName string, server models.Server) error {
+	runtimeServer := models.RuntimeServer{
+		Name: server.Name,
+	}
+	if d.HasChange("weight") {
+		runtimeServer.Weight = server.Weight
+	}
+	if d.HasChange("maintenance") {
+		runtimeServer.AdminState = "ready"
+		if server.Maintenance == "enabled" {
+			runtimeServer.AdminState = "maint"
+		}
+	}
+
+	err := client.Retry(ctx, func() error {
+		_, err := client.UpdateRuntimeServer(ctx, backendName, runtimeServer)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return withVersion(ctx, client, func(version int) error {
+		_, err := client.UpdateServerWithVersion(ctx, version, backendName, server)
+		return err
+	})
+}
+
+func resourceServerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	backendName := d.Get("backend").(string)
+
+	server := models.Server{
+		Name: d.Get("name").(string),
+	}
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		return client.DeleteServer(ctx, transactionId, backendName, server)
+	})
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func buildServerFromResourceParameters(d *schema.ResourceData) *models.Server {
+	weight := d.Get("weight").(int)
+	return &models.Server{
+		Name:        d.Get("name").(string),
+		Address:     d.Get("address").(string),
+		Port:        d.Get("port").(int),
+		Weight:      &weight,
+		Check:       d.Get("check").(string),
+		Inter:       d.Get("inter").(int),
+		Rise:        d.Get("rise").(int),
+		Fall:        d.Get("fall").(int),
+		Ssl:         d.Get("ssl").(string),
+		Verify:      d.Get("verify").(string),
+		Sni:         d.Get("sni").(string),
+		MaxConn:     d.Get("maxconn").(int),
+		Backup:      d.Get("backup").(string),
+		SendProxy:   d.Get("send_proxy").(string),
+		Maintenance: d.Get("maintenance").(string),
+	}
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+func TestResourceServer(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServerConfig("tfacc-server-backend", 10, "disabled"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_server.test", "name", "web1"),
+					resource.TestCheckResourceAttr("haproxy_server.test", "weight", "10"),
+					resource.TestCheckResourceAttr("haproxy_server.test", "id", "backend/tfacc-server-backend/server/web1"),
+				),
+			},
+			importStep("haproxy_server.test"),
+			{
+				Config: testAccServerConfig("tfacc-server-backend", 50, "enabled"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_server.test", "weight", "50"),
+					resource.TestCheckResourceAttr("haproxy_server.test", "maintenance", "enabled"),
+				),
+			},
+			importStep("haproxy_server.test"),
+		},
+	})
+}
+
+func testAccServerConfig(backendName string, weight int, maintenance string) string {
+	return fmt.Sprintf(`
+resource "haproxy_backend" "test" {
+	name = "%[1]s"
+	mode = "http"
+}
+
+resource "haproxy_server" "test" {
+	backend     = haproxy_backend.test.name
+	name        = "web1"
+	address     = "127.0.0.1"
+	port        = 8080
+	check       = "enabled"
+	inter       = 2000
+	rise        = 2
+	fall        = 3
+	weight      = %[2]d
+	maintenance = "%[3]s"
+}
+`, backendName, weight, maintenance)
+}
+
+// fakeServerAPI serves the server web1 of the backend app through the v2
+// layout, recording the runtime changes and the configuration writes.
+type fakeServerAPI struct {
+	mu           sync.Mutex
+	server       models.Server
+	runtime      []models.RuntimeServer
+	transactions int
+	versioned    int
+}
+
+func (f *fakeServerAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/configuration/raw"):
+		w.Write([]byte(`{"_version": 1, "data": ""}`))
+	case strings.HasSuffix(r.URL.Path, "/transactions"):
+		f.transactions++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"_version": 1, "id": "tx-1", "status": "in_progress"}`))
+	case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/runtime/servers/web1"):
+		server := models.RuntimeServer{}
+		json.NewDecoder(r.Body).Decode(&server)
+		f.runtime = append(f.runtime, server)
+		json.NewEncoder(w).Encode(server)
+	case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/configuration/servers/web1"):
+		if r.URL.Query().Get("version") != "" {
+			f.versioned++
+		}
+		json.NewDecoder(r.Body).Decode(&f.server)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(f.server)
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/configuration/servers/web1"):
+		json.NewEncoder(w).Encode(map[string]interface{}{"_version": 1, "data": f.server})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestServerWeightChangesAtRuntime(t *testing.T) {
+	weight := 10
+	api := &fakeServerAPI{server: models.Server{Name: "web1", Address: "10.0.0.1", Weight: &weight}}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client, err := haproxy.NewClient("admin", "adminpwd", server.URL, true, haproxy.APIVersionV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := resourceServer()
+	state := &terraform.InstanceState{
+		ID: "backend/app/server/web1",
+		Attributes: map[string]string{
+			"id":      "backend/app/server/web1",
+			"backend": "app",
+			"name":    "web1",
+			"address": "10.0.0.1",
+			"weight":  "10",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"backend": "app",
+		"name":    "web1",
+		"address": "10.0.0.1",
+		"weight":  20,
+	})
+
+	ctx := context.Background()
+	diff, err := r.Diff(ctx, state, config, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, diags := r.Apply(ctx, state, diff, client); diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+
+	if len(api.runtime) != 1 || api.runtime[0].Weight == nil || *api.runtime[0].Weight != 20 || api.runtime[0].AdminState != "" {
+		t.Fatalf("expected the weight alone to be set through the runtime server endpoint, got %+v", api.runtime)
+	}
+	if api.transactions != 0 || api.versioned != 1 || *api.server.Weight != 20 {
+		t.Fatalf("expected the weight persisted outside of a transaction, got %d transactions and %d versioned writes", api.transactions, api.versioned)
+	}
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy/models"
+)
+
+// tcpRuleList describes one of the tcp rule lists owned as a whole by a
+// resource: the tcp-request or the tcp-response rules of a section.
+type tcpRuleList struct {
+	keyword     string
+	parentTypes []string
+	types       []string
+	actions     []string
+	get         func(client *haproxy.Client, ctx context.Context, transactionId string, parentType string, parentName string) ([]models.TcpRule, error)
+	replace     func(client *haproxy.Client, ctx context.Context, transactionId string, parentType string, parentName string, rules []models.TcpRule) error
+}
+
+var tcpRequestRuleList = tcpRuleList{
+	keyword:     "tcp-request",
+	parentTypes: []string{"frontend", "backend"},
+	types:       []string{"connection", "content", "session", "inspect-delay"},
+	actions: []string{
+		"accept", "reject", "track-sc0", "track-sc1", "track-sc2", "set-var", "unset-var",
+		"expect-proxy", "capture", "lua",
+	},
+	get:     (*haproxy.Client).GetTcpRequestRules,
+	replace: (*haproxy.Client).ReplaceTcpRequestRules,
+}
+
+var tcpResponseRuleList = tcpRuleList{
+	keyword:     "tcp-response",
+	parentTypes: []string{"backend"},
+	types:       []string{"content", "inspect-delay"},
+	actions:     []string{"accept", "reject", "close", "set-var", "unset-var", "lua"},
+	get:         (*haproxy.Client).GetTcpResponseRules,
+	replace:     (*haproxy.Client).ReplaceTcpResponseRules,
+}
+
+func resourceTcpRequestRules() *schema.Resource {
+	return tcpRequestRuleList.resource("`haproxy_tcp_request_rules` manage the complete ordered list of tcp-request connection, content and session rules of a frontend or a backend.")
+}
+
+func resourceTcpResponseRules() *schema.Resource {
+	return tcpResponseRuleList.resource("`haproxy_tcp_response_rules` manage the complete ordered list of tcp-response content rules of a backend.")
+}
+
+func (l tcpRuleList) resource(description string) *schema.Resource {
+	return &schema.Resource{
+		Description:   description,
+		CreateContext: l.create,
+		ReadContext:   l.read,
+		UpdateContext: l.update,
+		DeleteContext: l.delete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importRuleList,
+		},
+		Timeouts: defaultTimeouts(),
+		Schema: map[string]*schema.Schema{
+			"parent_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the section the rules belong to.",
+			},
+			"parent_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  fmt.Sprintf("Type of the section the rules belong to. Possible value : '%s'.", joinValues(l.parentTypes)),
+				ValidateFunc: validation.StringInSlice(l.parentTypes, false),
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: fmt.Sprintf("Ordered list of %s rules. Rules not declared here are removed. https://cbonte.github.io/haproxy-dconv/1.8/configuration.html#4.2-%s", l.keyword, l.keyword),
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  fmt.Sprintf("Rule type. Possible value : '%s'.", joinValues(l.types)),
+							ValidateFunc: validation.StringInSlice(l.types, false),
+						},
+						"action": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  fmt.Sprintf("Rule action, not used by 'inspect-delay'. Possible value : '%s'.", joinValues(l.actions)),
+							ValidateFunc: validation.StringInSlice(l.actions, false),
+						},
+						"cond": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Condition type. Possible value : 'if' or 'unless'.",
+							ValidateFunc: validation.StringInSlice([]string{"if", "unless"}, false),
+						},
+						"cond_test": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ACL condition, e.g. '{ req.ssl_hello_type 1 }'.",
+						},
+						"timeout": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Timeout in milliseconds of the 'inspect-delay' type.",
+						},
+						"track_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Sample expression tracked by the 'track-sc' actions, e.g. 'src'.",
+						},
+						"track_table": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Stick table used by the 'track-sc' actions. Defaults to the table of the current section.",
+						},
+						"var_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Variable name of the 'set-var' and 'unset-var' actions.",
+						},
+						"var_scope": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Variable scope, e.g. 'txn', 'req' or 'sess'.",
+						},
+						"expr": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Sample expression assigned by the 'set-var' action.",
+						},
+						"capture_sample": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Sample expression captured by the 'capture' action.",
+						},
+						"capture_len": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Maximum length of the sample captured by the 'capture' action.",
+						},
+						"lua_action": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Lua action registered with core.register_action, used by the 'lua' action.",
+						},
+						"lua_params": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Parameters passed to the lua action.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (l tcpRuleList) read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+
+	rules, err := l.get(client, ctx, "", d.Get("parent_type").(string), d.Get("parent_name").(string))
+	if errors.Is(err, haproxy.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+
+	d.Set("rule", flattenTcpRules(rules))
+
+	return nil
+}
+
+func (l tcpRuleList) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := l.apply(ctx, d, meta, buildTcpRulesFromResourceParameters(d))
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(d.Get("parent_type").(string) + "/" + d.Get("parent_name").(string))
+	return l.read(ctx, d, meta)
+}
+
+func (l tcpRuleList) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := l.apply(ctx, d, meta, buildTcpRulesFromResourceParameters(d))
+	if diags.HasError() {
+		return diags
+	}
+	return l.read(ctx, d, meta)
+}
+
+func (l tcpRuleList) delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := l.apply(ctx, d, meta, nil)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId("")
+	return nil
+}
+
+func (l tcpRuleList) apply(ctx context.Context, d *schema.ResourceData, meta interface{}, rules []models.TcpRule) diag.Diagnostics {
+	client := meta.(*haproxy.Client)
+	parentType := d.Get("parent_type").(string)
+	parentName := d.Get("parent_name").(string)
+
+	err := withTransaction(ctx, client, func(transactionId string) error {
+		return l.replace(client, ctx, transactionId, parentType, parentName, rules)
+	})
+	if err != nil {
+		return apiDiagnostics(d, err)
+	}
+	return nil
+}
+
+func buildTcpRulesFromResourceParameters(d *schema.ResourceData) []models.TcpRule {
+	items := d.Get("rule").([]interface{})
+	rules := make([]models.TcpRule, 0, len(items))
+	for _, item := range items {
+		v := item.(map[string]interface{})
+		rules = append(rules, models.TcpRule{
+			Type:          v["type"].(string),
+			Action:        v["action"].(string),
+			Cond:          v["cond"].(string),
+			CondTest:      v["cond_test"].(string),
+			Timeout:       v["timeout"].(int),
+			TrackKey:      v["track_key"].(string),
+			TrackTable:    v["track_table"].(string),
+			VarName:       v["var_name"].(string),
+			VarScope:      v["var_scope"].(string),
+			Expr:          v["expr"].(string),
+			CaptureSample: v["capture_sample"].(string),
+			CaptureLen:    v["capture_len"].(int),
+			LuaAction:     v["lua_action"].(string),
+			LuaParams:     v["lua_params"].(string),
+		})
+	}
+	return rules
+}
+
+func flattenTcpRules(rules []models.TcpRule) []interface{} {
+	items := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, map[string]interface{}{
+			"type":           rule.Type,
+			"action":         rule.Action,
+			"cond":           rule.Cond,
+			"cond_test":      rule.CondTest,
+			"timeout":        rule.Timeout,
+			"track_key":      rule.TrackKey,
+			"track_table":    rule.TrackTable,
+			"var_name":       rule.VarName,
+			"var_scope":      rule.VarScope,
+			"expr":           rule.Expr,
+			"capture_sample": rule.CaptureSample,
+			"capture_len":    rule.CaptureLen,
+			"lua_action":     rule.LuaAction,
+			"lua_params":     rule.LuaParams,
+		})
+	}
+	return items
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestResourceTcpRequestRules(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTcpRequestRulesConfig("tfacc-tcp-rules", 5000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_tcp_request_rules.test", "id", "frontend/tfacc-tcp-rules"),
+					resource.TestCheckResourceAttr("haproxy_tcp_request_rules.test", "rule.#", "3"),
+					resource.TestCheckResourceAttr("haproxy_tcp_request_rules.test", "rule.0.timeout", "5000"),
+				),
+			},
+			importStep("haproxy_tcp_request_rules.test"),
+			{
+				Config: testAccTcpRequestRulesConfig("tfacc-tcp-rules", 2000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_tcp_request_rules.test", "rule.0.timeout", "2000"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceTcpResponseRules(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTcpResponseRulesConfig("tfacc-tcp-response-rules"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("haproxy_tcp_response_rules.test", "rule.#", "2"),
+				),
+			},
+			importStep("haproxy_tcp_response_rules.test"),
+		},
+	})
+}
+
+func testAccTcpRequestRulesConfig(frontendName string, inspectDelay int) string {
+	return fmt.Sprintf(`
+resource "haproxy_frontend" "test" {
+	name = "%[1]s"
+	mode = "tcp"
+}
+
+resource "haproxy_tcp_request_rules" "test" {
+	parent_type = "frontend"
+	parent_name = haproxy_frontend.test.name
+
+	rule {
+		type    = "inspect-delay"
+		timeout = %[2]d
+	}
+
+	rule {
+		type      = "connection"
+		action    = "reject"
+		cond      = "if"
+		cond_test = "{ src 192.0.2.0/24 }"
+	}
+
+	rule {
+		type      = "content"
+		action    = "accept"
+		cond      = "if"
+		cond_test = "{ req.ssl_hello_type 1 }"
+	}
+}
+`, frontendName, inspectDelay)
+}
+
+func testAccTcpResponseRulesConfig(backendName string) string {
+	return fmt.Sprintf(`
+resource "haproxy_backend" "test" {
+	name = "%[1]s"
+	mode = "tcp"
+}
+
+resource "haproxy_tcp_response_rules" "test" {
+	parent_type = "backend"
+	parent_name = haproxy_backend.test.name
+
+	rule {
+		type    = "inspect-delay"
+		timeout = 1000
+	}
+
+	rule {
+		type      = "content"
+		action    = "accept"
+		cond      = "if"
+		cond_test = "{ res.len gt 0 }"
+	}
+}
+`, backendName)
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/matthisholleville/terraform-provider-haproxy/internal/haproxy"
+)
+
+// withTransaction runs fn inside the transaction shared by every change of
+// the current apply. The whole sequence is retried, so a version bumped by a
+// concurrent change is picked up on the next attempt.
+func withTransaction(ctx context.Context, client *haproxy.Client, fn func(transactionId string) error) error {
+	return client.Retry(ctx, func() error {
+		return client.WithTransaction(ctx, fn)
+	})
+}
+
+// withVersion runs fn against the current configuration version without
+// opening a transaction, retrying when the version moved in the meantime.
+func withVersion(ctx context.Context, client *haproxy.Client, fn func(version int) error) error {
+	return client.Retry(ctx, func() error {
+		configuration, err := client.GetConfiguration(ctx)
+		if err != nil {
+			return err
+		}
+		return fn(configuration.Version)
+	})
+}
+
+// defaultTimeouts bounds the changes of a resource, retries included. They
+// can be overridden with a timeouts block.
+func defaultTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(5 * time.Minute),
+		Update: schema.DefaultTimeout(5 * time.Minute),
+		Delete: schema.DefaultTimeout(5 * time.Minute),
+	}
+}
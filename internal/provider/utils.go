@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var enabledDisabled = []string{"enabled", "disabled"}
+
+// singleBlock returns the attributes of a nested block limited to one item.
+func singleBlock(d *schema.ResourceData, key string) (map[string]interface{}, bool) {
+	v, ok := d.GetOk(key)
+	if !ok {
+		return nil, false
+	}
+	items := v.(*schema.Set).List()
+	if len(items) == 0 || items[0] == nil {
+		return nil, false
+	}
+	return items[0].(map[string]interface{}), true
+}
+
+// joinValues formats a list of allowed values for a schema description.
+func joinValues(values []string) string {
+	return strings.Join(values, "', '")
+}
+
+// insertIndex returns the position a new item must be inserted at in an
+// index addressed list: the configured index, capped to the end of the list,
+// or the end of the list when no index is configured.
+func insertIndex(d *schema.ResourceData, length int) int {
+	index := length
+	if v := d.GetRawConfig().GetAttr("index"); !v.IsNull() && v.IsKnown() {
+		configured := d.Get("index").(int)
+		if configured < length {
+			index = configured
+		}
+	}
+	return index
+}
+
+// findByContent looks up an item of an index addressed list by content.
+// When identical items exist, the one at the hinted index is preferred. If
+// nothing matches, the item at the hinted index is adopted when it still has
+// the same identity, so out of band edits show up as a diff instead of a
+// duplicate being created.
+func findByContent(count int, hint int, matches func(i int) bool, sameIdentity func(i int) bool) (int, bool) {
+	found := -1
+	for i := 0; i < count; i++ {
+		if !matches(i) {
+			continue
+		}
+		if i == hint {
+			return i, true
+		}
+		if found == -1 {
+			found = i
+		}
+	}
+	if found != -1 {
+		return found, true
+	}
+	if hint >= 0 && hint < count && sameIdentity(hint) {
+		return hint, true
+	}
+	return 0, false
+}
@@ -14,9 +14,10 @@ dataplaneapi {
   }
 
   resources {
-    maps_dir      = "/etc/haproxy/maps"
-    ssl_certs_dir = "/etc/haproxy/ssl"
-    spoe_dir      = "/etc/haproxy/spoe"
+    maps_dir            = "/etc/haproxy/maps"
+    ssl_certs_dir       = "/etc/haproxy/ssl"
+    spoe_dir            = "/etc/haproxy/spoe"
+    general_storage_dir = "/etc/haproxy/general"
   }
 
   advertised {}